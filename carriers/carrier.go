@@ -0,0 +1,51 @@
+package carriers
+
+import (
+	"fmt"
+	"time"
+)
+
+// TrackingEvent — событие отслеживания в общем для всех перевозчиков виде.
+// Status — один из models.ShipmentStatus*.
+type TrackingEvent struct {
+	Status      string    `json:"status"`
+	Description string    `json:"description"`
+	Location    string    `json:"location"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// Carrier — служба доставки. Track возвращает полную историю посылки;
+// ручной перевозчик ничего не возвращает, события для него вносит администратор.
+type Carrier interface {
+	Code() string
+	Name() string
+	Track(trackingNumber string) ([]TrackingEvent, error)
+}
+
+type Registry struct {
+	carriers map[string]Carrier
+}
+
+func NewRegistry(list ...Carrier) *Registry {
+	r := &Registry{carriers: make(map[string]Carrier, len(list))}
+	for _, c := range list {
+		r.carriers[c.Code()] = c
+	}
+	return r
+}
+
+func (r *Registry) Get(code string) (Carrier, error) {
+	c, ok := r.carriers[code]
+	if !ok {
+		return nil, fmt.Errorf("unknown carrier %q", code)
+	}
+	return c, nil
+}
+
+func (r *Registry) Codes() []string {
+	codes := make([]string, 0, len(r.carriers))
+	for code := range r.carriers {
+		codes = append(codes, code)
+	}
+	return codes
+}
@@ -0,0 +1,64 @@
+package carriers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPCarrier опрашивает API курьерской службы:
+// GET {baseURL}/tracking/{number} -> {"events": [...]}.
+type HTTPCarrier struct {
+	code    string
+	name    string
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+func NewHTTPCarrier(code, name, baseURL, apiKey string) *HTTPCarrier {
+	return &HTTPCarrier{
+		code:    code,
+		name:    name,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *HTTPCarrier) Code() string { return c.code }
+
+func (c *HTTPCarrier) Name() string { return c.name }
+
+type trackingResponse struct {
+	Events []TrackingEvent `json:"events"`
+}
+
+func (c *HTTPCarrier) Track(trackingNumber string) ([]TrackingEvent, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/tracking/"+url.PathEscape(trackingNumber), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("carrier %s request failed: %w", c.code, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("carrier %s returned status %d", c.code, resp.StatusCode)
+	}
+
+	var body trackingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("carrier %s returned invalid response: %w", c.code, err)
+	}
+	return body.Events, nil
+}
@@ -0,0 +1,131 @@
+package carriers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"1kosmetika-marketplace-backend/models"
+)
+
+func TestHTTPCarrierTrackMock(t *testing.T) {
+	srv := httptest.NewServer(MockHandler())
+	defer srv.Close()
+
+	carrier := NewHTTPCarrier(MockCode, "Mock", srv.URL+"/", "")
+	events, err := carrier.Track("KOS-000123")
+	if err != nil {
+		t.Fatalf("Track: %v", err)
+	}
+	if len(events) == 0 {
+		t.Fatal("expected at least the pickup event")
+	}
+
+	want := []string{
+		models.ShipmentStatusInTransit,
+		models.ShipmentStatusInTransit,
+		models.ShipmentStatusOutForDelivery,
+		models.ShipmentStatusDelivered,
+	}
+	now := time.Now()
+	for i, e := range events {
+		if e.Status != want[i] {
+			t.Errorf("event %d: status %q, want %q", i, e.Status, want[i])
+		}
+		if e.OccurredAt.IsZero() || e.OccurredAt.After(now) {
+			t.Errorf("event %d: unexpected occurred_at %v", i, e.OccurredAt)
+		}
+		if i > 0 && e.OccurredAt.Before(events[i-1].OccurredAt) {
+			t.Errorf("event %d is earlier than the previous one", i)
+		}
+		if e.Description == "" {
+			t.Errorf("event %d has no description", i)
+		}
+	}
+}
+
+func TestMockEventsProgress(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 30, 0, 0, time.UTC)
+	offsets := []time.Duration{0, 12 * time.Hour, 36 * time.Hour, 48 * time.Hour}
+
+	delivered, inProgress := 0, 0
+	for i := 0; i < 200; i++ {
+		number := fmt.Sprintf("KOS-%06d", i)
+		events := mockEvents(number, now)
+		if again := mockEvents(number, now); len(again) != len(events) {
+			t.Fatalf("%s: mock tracking is not deterministic", number)
+		}
+		if len(events) == 0 {
+			t.Fatalf("%s: expected at least the pickup event", number)
+		}
+
+		// Число событий определяется тем, сколько прошло с отправки.
+		age := now.Sub(events[0].OccurredAt)
+		want := 0
+		for _, offset := range offsets {
+			if offset <= age {
+				want++
+			}
+		}
+		if len(events) != want {
+			t.Errorf("%s: %d events after %v, want %d", number, len(events), age, want)
+		}
+
+		if events[len(events)-1].Status == models.ShipmentStatusDelivered {
+			delivered++
+		} else {
+			inProgress++
+		}
+	}
+	if delivered == 0 || inProgress == 0 {
+		t.Errorf("expected both delivered and in-progress parcels, got %d/%d", delivered, inProgress)
+	}
+}
+
+func TestHTTPCarrierTrackErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"not found", func(w http.ResponseWriter, r *http.Request) { http.NotFound(w, r) }},
+		{"server error", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) }},
+		{"invalid json", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("{")) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+
+			if _, err := NewHTTPCarrier("test", "Test", srv.URL, "").Track("X1"); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestHTTPCarrierSendsAPIKey(t *testing.T) {
+	var gotAuth, gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotPath = r.URL.EscapedPath()
+		_, _ = w.Write([]byte(`{"events":[{"status":"delivered","description":"ok","occurred_at":"2025-03-10T12:00:00Z"}]}`))
+	}))
+	defer srv.Close()
+
+	events, err := NewHTTPCarrier("test", "Test", srv.URL, "secret").Track("A/B 1")
+	if err != nil {
+		t.Fatalf("Track: %v", err)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	if gotPath != "/tracking/A%2FB%201" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if len(events) != 1 || events[0].Status != models.ShipmentStatusDelivered ||
+		!events[0].OccurredAt.Equal(time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
@@ -0,0 +1,16 @@
+package carriers
+
+const ManualCode = "manual"
+
+// ManualCarrier — собственная курьерская доставка без внешнего API.
+type ManualCarrier struct{}
+
+func NewManualCarrier() *ManualCarrier { return &ManualCarrier{} }
+
+func (c *ManualCarrier) Code() string { return ManualCode }
+
+func (c *ManualCarrier) Name() string { return "Курьер магазина" }
+
+func (c *ManualCarrier) Track(trackingNumber string) ([]TrackingEvent, error) {
+	return nil, nil
+}
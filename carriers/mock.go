@@ -0,0 +1,54 @@
+package carriers
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+
+	"1kosmetika-marketplace-backend/models"
+)
+
+const MockCode = "mock"
+
+// MockHandler имитирует API курьерской службы для HTTPCarrier в dev-окружении.
+// Посылка проходит статусы по часам от условного времени отправки,
+// которое детерминированно выводится из трек-номера.
+func MockHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		number := strings.TrimPrefix(r.URL.Path, "/tracking/")
+		if r.Method != http.MethodGet || number == "" || number == r.URL.Path {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(trackingResponse{Events: mockEvents(number, time.Now())})
+	})
+}
+
+func mockEvents(number string, now time.Time) []TrackingEvent {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(number))
+	shippedAt := now.Truncate(time.Hour).Add(-time.Duration(h.Sum32()%72) * time.Hour)
+
+	steps := []TrackingEvent{
+		{Status: models.ShipmentStatusInTransit, Description: "Посылка принята курьерской службой", Location: "Ашхабад"},
+		{Status: models.ShipmentStatusInTransit, Description: "Посылка в сортировочном центре", Location: "Ашхабад"},
+		{Status: models.ShipmentStatusOutForDelivery, Description: "Посылка передана курьеру"},
+		{Status: models.ShipmentStatusDelivered, Description: "Посылка вручена получателю"},
+	}
+	offsets := []time.Duration{0, 12 * time.Hour, 36 * time.Hour, 48 * time.Hour}
+
+	events := make([]TrackingEvent, 0, len(steps))
+	for i, step := range steps {
+		at := shippedAt.Add(offsets[i])
+		if at.After(now) {
+			break
+		}
+		step.OccurredAt = at
+		events = append(events, step)
+	}
+	return events
+}
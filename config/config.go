@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -19,6 +20,34 @@ type Config struct {
 	SMTPUser   string
 	SMTPPass   string
 	FromEmail  string
+	SiteURL    string
+
+	AbandonedCartHours int
+
+	// true — цены на витрине уже включают налог, false — налог начисляется сверху.
+	PricesIncludeTax bool
+
+	// Валюта, в которой хранятся цены и оформляются заказы.
+	BaseCurrency string
+
+	// true — отзыв могут оставить только покупатели товара.
+	ReviewsRequirePurchase bool
+	// После стольких жалоб отзыв снимается с витрины до модерации; 0 — никогда.
+	ReviewReportThreshold int
+
+	// Курьерская служба с HTTP API; пустой URL — только ручная доставка.
+	CarrierAPIURL        string
+	CarrierAPIKey        string
+	CarrierWebhookSecret string
+	MockCarrier          bool
+
+	// Реквизиты продавца для счетов.
+	CompanyName    string
+	CompanyAddress string
+	CompanyTaxID   string
+	CompanyPhone   string
+	CompanyEmail   string
+	InvoicePrefix  string
 }
 
 func Load() *Config {
@@ -38,6 +67,28 @@ func Load() *Config {
 		SMTPUser:   getEnv("SMTP_USER", ""),
 		SMTPPass:   getEnv("SMTP_PASS", ""),
 		FromEmail:  getEnv("FROM_EMAIL", ""),
+		SiteURL:    getEnv("SITE_URL", "http://localhost:8080"),
+
+		AbandonedCartHours: getEnvInt("ABANDONED_CART_HOURS", 24),
+
+		PricesIncludeTax: getEnv("PRICES_INCLUDE_TAX", "true") != "false",
+
+		BaseCurrency: getEnv("BASE_CURRENCY", "TMT"),
+
+		ReviewsRequirePurchase: getEnv("REVIEWS_REQUIRE_PURCHASE", "false") == "true",
+		ReviewReportThreshold:  getEnvInt("REVIEW_REPORT_THRESHOLD", 3),
+
+		CarrierAPIURL:        getEnv("CARRIER_API_URL", ""),
+		CarrierAPIKey:        getEnv("CARRIER_API_KEY", ""),
+		CarrierWebhookSecret: getEnv("CARRIER_WEBHOOK_SECRET", ""),
+		MockCarrier:          getEnv("MOCK_CARRIER", "") == "true",
+
+		CompanyName:    getEnv("COMPANY_NAME", "1Kosmetika"),
+		CompanyAddress: getEnv("COMPANY_ADDRESS", ""),
+		CompanyTaxID:   getEnv("COMPANY_TAX_ID", ""),
+		CompanyPhone:   getEnv("COMPANY_PHONE", ""),
+		CompanyEmail:   getEnv("COMPANY_EMAIL", ""),
+		InvoicePrefix:  getEnv("INVOICE_PREFIX", "INV"),
 	}
 }
 
@@ -54,3 +105,12 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
@@ -6,6 +6,8 @@ import (
 
 	"1kosmetika-marketplace-backend/config"
 	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
+	"1kosmetika-marketplace-backend/utils"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -44,7 +46,7 @@ func Migrate() error {
 				SELECT 1 FROM information_schema.columns
 				WHERE table_name='order_products' AND column_name='price'
 			) THEN
-				ALTER TABLE order_products ADD COLUMN price numeric DEFAULT 0;
+				ALTER TABLE order_products ADD COLUMN price bigint DEFAULT 0;
 			END IF;
 		END$$;
 	`)
@@ -79,15 +81,55 @@ func Migrate() error {
 	`)
 
 
+	// Отзывы, написанные до модерации, уже были на витрине — они одобрены.
+	DB.Exec(`
+		DO $$
+		BEGIN
+			IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name='reviews') THEN
+				ALTER TABLE reviews ADD COLUMN IF NOT EXISTS status varchar(20) NOT NULL DEFAULT 'approved';
+			END IF;
+		END$$;
+	`)
+
+	if err := convertMoneyColumns(); err != nil {
+		return fmt.Errorf("money column conversion failed: %w", err)
+	}
+
 	err := DB.AutoMigrate(
 		&models.User{},
 		&models.Product{},
+		&models.ProductSlugRedirect{},
+		&models.PriceHistory{},
 		&models.Order{},
 		&models.OrderProduct{},
+		&models.Address{},
+		&models.ShippingMethod{},
+		&models.ShippingRate{},
+		&models.Shipment{},
+		&models.ShipmentEvent{},
+		&models.Invoice{},
+		&models.TaxRate{},
+		&models.ExchangeRate{},
 		&models.Cart{},
 		&models.CartItem{},
+		&models.CartReminder{},
 		&models.Review{},
+		&models.ReviewPhoto{},
+		&models.ReviewVote{},
+		&models.ReviewReply{},
+		&models.ReviewReport{},
+		&models.ProductQuestion{},
+		&models.ProductAnswer{},
+		&models.AnswerVote{},
+		&models.ProductRelation{},
+		&models.UserRecommendation{},
+		&models.RecentView{},
+		&models.BeautyProfile{},
+		&models.Ingredient{},
+		&models.ProductIngredient{},
+		&models.Wishlist{},
 		&models.Favorite{},
+		&models.StockSubscription{},
 		&models.Notification{},
 		&models.DailyStats{},
 	)
@@ -95,6 +137,144 @@ func Migrate() error {
 		return fmt.Errorf("database migration failed: %w", err)
 	}
 
+	if err := backfillProductSlugs(); err != nil {
+		return fmt.Errorf("product slug backfill failed: %w", err)
+	}
+
+	DB.Exec(`UPDATE cart_items SET unit_price = ROUND(price::numeric / quantity) WHERE unit_price = 0 AND quantity > 0`)
+	// Счётчик номеров счетов — одна строка: её блокировка делает нумерацию
+	// сплошной, а откат транзакции возвращает номер.
+	if err := DB.Exec(`CREATE TABLE IF NOT EXISTS invoice_counter (id INT PRIMARY KEY, last_number BIGINT NOT NULL)`).Error; err != nil {
+		return fmt.Errorf("invoice counter creation failed: %w", err)
+	}
+	DB.Exec(`INSERT INTO invoice_counter (id, last_number) VALUES (1, 0) ON CONFLICT (id) DO NOTHING`)
+
+	DB.Exec(`UPDATE orders SET currency = ? WHERE currency IS NULL OR currency = ''`, money.BaseCurrency())
+	// Старые заказы оформлены без доставки: сумма товаров равна итогу.
+	DB.Exec(`UPDATE orders SET subtotal = total WHERE subtotal = 0 AND shipping_cost = 0`)
+	// До налогового модуля налог не считался: вся сумма строки — без налога.
+	DB.Exec(`UPDATE order_products SET net_amount = price * quantity WHERE net_amount = 0 AND tax_amount = 0`)
+
+	// Сводки рейтинга для отзывов, оставленных до их появления.
+	DB.Exec(`
+		UPDATE products p SET
+			rating_avg = COALESCE((SELECT ROUND(AVG(rating)::numeric, 2) FROM reviews WHERE product_id = p.id AND status = 'approved'), 0),
+			rating_count = (SELECT COUNT(*) FROM reviews WHERE product_id = p.id AND status = 'approved')
+		WHERE p.rating_count = 0`)
+
+	if err := migrateFavoritesToWishlists(); err != nil {
+		return fmt.Errorf("wishlist migration failed: %w", err)
+	}
+
+	// INCI-название ингредиента уникально без учёта регистра.
+	if err := DB.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_ingredients_inci_name ON ingredients (LOWER(inci_name))`).Error; err != nil {
+		return fmt.Errorf("ingredient index creation failed: %w", err)
+	}
+
 	log.Println("✅ Database migration completed")
 	return nil
 }
+
+
+// Денежные колонки хранятся в копейках (bigint). Колонки старого формата
+// (numeric, double) переводятся один раз с округлением до копейки.
+var moneyColumns = map[string][]string{
+	"products":         {"price", "compare_at_price", "sale_price"},
+	"cart_items":       {"unit_price", "price"},
+	"orders":           {"total", "total_amount", "shipping_cost", "subtotal", "tax_total"},
+	"order_products":   {"price", "tax_amount", "net_amount"},
+	"price_histories":  {"old_price", "new_price"},
+	"cart_reminders":   {"cart_value"},
+	"shipping_methods": {"free_shipping_threshold"},
+	"shipping_rates":   {"price"},
+	"invoices":         {"total"},
+	"daily_stats":      {"total_revenue", "total_shipping", "total_tax"},
+}
+
+func convertMoneyColumns() error {
+	for table, columns := range moneyColumns {
+		for _, column := range columns {
+			var dataType string
+			err := DB.Raw(`SELECT data_type FROM information_schema.columns WHERE table_name = ? AND column_name = ?`,
+				table, column).Scan(&dataType).Error
+			if err != nil {
+				return err
+			}
+			if dataType == "" || dataType == "bigint" {
+				continue
+			}
+			sql := fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN %s TYPE bigint USING ROUND(%s * %d)`,
+				table, column, column, money.Scale)
+			if err := DB.Exec(sql).Error; err != nil {
+				return fmt.Errorf("%s.%s: %w", table, column, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Избранное до появления списков переносится в список по умолчанию.
+func migrateFavoritesToWishlists() error {
+	if err := DB.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_wishlists_user_default ON wishlists (user_id) WHERE is_default`).Error; err != nil {
+		return err
+	}
+	err := DB.Exec(`
+		INSERT INTO wishlists (user_id, name, is_default, created_at, updated_at)
+		SELECT DISTINCT f.user_id, ?, true, NOW(), NOW() FROM favorites f
+		WHERE f.wishlist_id IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM wishlists w WHERE w.user_id = f.user_id AND w.is_default)
+	`, models.DefaultWishlistName).Error
+	if err != nil {
+		return err
+	}
+	// Старая таблица не запрещала повторы: до переноса оставляем одну строку
+	// на товар, иначе перенос упрётся в idx_favorites_wishlist_product.
+	err = DB.Exec(`
+		DELETE FROM favorites f
+		WHERE f.wishlist_id IS NULL AND (
+			EXISTS (SELECT 1 FROM favorites d
+				WHERE d.wishlist_id IS NULL AND d.user_id = f.user_id AND d.product_id = f.product_id AND d.id < f.id)
+			OR EXISTS (SELECT 1 FROM favorites d JOIN wishlists w ON w.id = d.wishlist_id
+				WHERE w.user_id = f.user_id AND w.is_default AND d.product_id = f.product_id)
+		)
+	`).Error
+	if err != nil {
+		return err
+	}
+	return DB.Exec(`
+		UPDATE favorites f SET wishlist_id = w.id FROM wishlists w
+		WHERE f.wishlist_id IS NULL AND w.user_id = f.user_id AND w.is_default
+	`).Error
+}
+
+// Товары, созданные до появления SKU и slug, получают их при первой миграции.
+func backfillProductSlugs() error {
+	if err := DB.Exec(`UPDATE products SET sku = 'KOS-' || LPAD(id::text, 8, '0') WHERE sku IS NULL OR sku = ''`).Error; err != nil {
+		return err
+	}
+
+	var products []models.Product
+	if err := DB.Where("slug IS NULL OR slug = ''").Find(&products).Error; err != nil {
+		return err
+	}
+
+	for _, p := range products {
+		base := utils.Slugify(p.Name)
+		if base == "" {
+			base = "product"
+		}
+		slug := base
+		for i := 2; ; i++ {
+			var count int64
+			DB.Model(&models.Product{}).Where("slug = ?", slug).Count(&count)
+			if count == 0 {
+				break
+			}
+			slug = fmt.Sprintf("%s-%d", base, i)
+		}
+		if err := DB.Model(&models.Product{}).Where("id = ?", p.ID).Update("slug", slug).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
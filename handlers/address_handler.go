@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AddressHandler struct {
+	addressService services.AddressService
+}
+
+func NewAddressHandler(addressService services.AddressService) *AddressHandler {
+	return &AddressHandler{addressService: addressService}
+}
+
+func (h *AddressHandler) GetAddresses(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	addresses, err := h.addressService.GetAddresses(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get addresses"})
+		return
+	}
+	c.JSON(http.StatusOK, addresses)
+}
+
+func (h *AddressHandler) CreateAddress(c *gin.Context) {
+	var address models.Address
+	if err := c.ShouldBindJSON(&address); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address data: " + err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if err := h.addressService.CreateAddress(userID, &address); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, address)
+}
+
+func (h *AddressHandler) UpdateAddress(c *gin.Context) {
+	addressID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address ID"})
+		return
+	}
+
+	var input models.Address
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address data: " + err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	address, err := h.addressService.UpdateAddress(userID, uint(addressID), &input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, address)
+}
+
+func (h *AddressHandler) DeleteAddress(c *gin.Context) {
+	addressID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address ID"})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if err := h.addressService.DeleteAddress(userID, uint(addressID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Address deleted"})
+}
+
+func (h *AddressHandler) SetDefaultAddress(c *gin.Context) {
+	addressID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address ID"})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if err := h.addressService.SetDefaultAddress(userID, uint(addressID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Default address updated"})
+}
+
+func (h *AddressHandler) GetRegions(c *gin.Context) {
+	c.JSON(http.StatusOK, models.Regions)
+}
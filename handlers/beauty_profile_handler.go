@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BeautyProfileHandler struct {
+	beautyProfileService services.BeautyProfileService
+}
+
+func NewBeautyProfileHandler(beautyProfileService services.BeautyProfileService) *BeautyProfileHandler {
+	return &BeautyProfileHandler{beautyProfileService: beautyProfileService}
+}
+
+// GetOptions — допустимые значения для профиля и атрибутов товара.
+func (h *BeautyProfileHandler) GetOptions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"skin_types": models.SkinTypes,
+		"skin_tones": models.SkinTones,
+		"hair_types": models.HairTypes,
+		"concerns":   models.BeautyConcerns,
+	})
+}
+
+func (h *BeautyProfileHandler) GetProfile(c *gin.Context) {
+	profile, err := h.beautyProfileService.GetProfile(c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get beauty profile"})
+		return
+	}
+	if profile == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Beauty profile not found"})
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *BeautyProfileHandler) SaveProfile(c *gin.Context) {
+	var req services.BeautyProfileInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.beautyProfileService.SaveProfile(c.GetUint("user_id"), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *BeautyProfileHandler) DeleteProfile(c *gin.Context) {
+	if err := h.beautyProfileService.DeleteProfile(c.GetUint("user_id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Beauty profile deleted"})
+}
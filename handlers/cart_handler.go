@@ -9,11 +9,12 @@ import (
 )
 
 type CartHandler struct {
-	cartService services.CartService
+	cartService     services.CartService
+	currencyService services.CurrencyService
 }
 
-func NewCartHandler(cartService services.CartService) *CartHandler {
-	return &CartHandler{cartService: cartService}
+func NewCartHandler(cartService services.CartService, currencyService services.CurrencyService) *CartHandler {
+	return &CartHandler{cartService: cartService, currencyService: currencyService}
 }
 
 type AddToCartRequest struct {
@@ -21,13 +22,35 @@ type AddToCartRequest struct {
 	Quantity  int  `json:"quantity" binding:"required,min=1"`
 }
 
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity" binding:"required,min=1"`
+}
+
+type SetCartRequest struct {
+	Items []AddToCartRequest `json:"items" binding:"dive"`
+}
+
+func (r SetCartRequest) toInputs() []services.CartItemInput {
+	inputs := make([]services.CartItemInput, 0, len(r.Items))
+	for _, item := range r.Items {
+		inputs = append(inputs, services.CartItemInput{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	return inputs
+}
+
 func (h *CartHandler) GetCart(c *gin.Context) {
+	conv, ok := displayConverter(c, h.currencyService)
+	if !ok {
+		return
+	}
+
 	userID := c.GetUint("user_id")
 	cart, err := h.cartService.GetCart(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get cart"})
 		return
 	}
+	cart.ApplyCurrency(conv)
 	c.JSON(http.StatusOK, cart)
 }
 
@@ -48,6 +71,76 @@ func (h *CartHandler) AddToCart(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Item added to cart successfully"})
 }
 
+func (h *CartHandler) UpdateCartItem(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	var req UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.cartService.UpdateCartItem(userID, uint(itemID), req.Quantity); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cart item updated successfully"})
+}
+
+func (h *CartHandler) SetCart(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	var req SetCartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cart, err := h.cartService.SetCart(userID, req.toInputs())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+func (h *CartHandler) MergeCart(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	var req SetCartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cart, err := h.cartService.MergeCart(userID, req.toInputs())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge cart"})
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+func (h *CartHandler) MoveToFavorites(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	if err := h.cartService.MoveToFavorites(userID, uint(itemID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item moved to favorites"})
+}
+
 func (h *CartHandler) RemoveFromCart(c *gin.Context) {
 	userID := c.GetUint("user_id")
 	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -72,4 +165,81 @@ func (h *CartHandler) ClearCart(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Cart cleared successfully"})
-}
\ No newline at end of file
+}
+
+// ---- Гостевая корзина: владелец определяется middleware GuestSession ----
+
+func (h *CartHandler) GetGuestCart(c *gin.Context) {
+	conv, ok := displayConverter(c, h.currencyService)
+	if !ok {
+		return
+	}
+
+	cart, err := h.cartService.GetGuestCart(c.GetString("guest_token"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get cart"})
+		return
+	}
+	cart.ApplyCurrency(conv)
+	c.JSON(http.StatusOK, cart)
+}
+
+func (h *CartHandler) AddToGuestCart(c *gin.Context) {
+	var req AddToCartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.cartService.AddToGuestCart(c.GetString("guest_token"), req.ProductID, req.Quantity); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item added to cart successfully"})
+}
+
+func (h *CartHandler) UpdateGuestCartItem(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	var req UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.cartService.UpdateGuestCartItem(c.GetString("guest_token"), uint(itemID), req.Quantity); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cart item updated successfully"})
+}
+
+func (h *CartHandler) RemoveFromGuestCart(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	if err := h.cartService.RemoveFromGuestCart(c.GetString("guest_token"), uint(itemID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove item from cart"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item removed from cart successfully"})
+}
+
+func (h *CartHandler) ClearGuestCart(c *gin.Context) {
+	if err := h.cartService.ClearGuestCart(c.GetString("guest_token")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear cart"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cart cleared successfully"})
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"1kosmetika-marketplace-backend/money"
+	"1kosmetika-marketplace-backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CurrencyHandler struct {
+	currencyService services.CurrencyService
+}
+
+func NewCurrencyHandler(currencyService services.CurrencyService) *CurrencyHandler {
+	return &CurrencyHandler{currencyService: currencyService}
+}
+
+type SetExchangeRateRequest struct {
+	Rate float64 `json:"rate" binding:"required,gt=0"`
+}
+
+// GetCurrencies — публичный список валют, доступных для ?currency=.
+func (h *CurrencyHandler) GetCurrencies(c *gin.Context) {
+	rates, err := h.currencyService.GetRates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get currencies"})
+		return
+	}
+
+	currencies := []string{money.BaseCurrency()}
+	for _, rate := range rates {
+		currencies = append(currencies, rate.Currency)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"base":       money.BaseCurrency(),
+		"currencies": currencies,
+	})
+}
+
+func (h *CurrencyHandler) GetExchangeRates(c *gin.Context) {
+	rates, err := h.currencyService.GetRates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get exchange rates"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"base":  money.BaseCurrency(),
+		"rates": rates,
+	})
+}
+
+func (h *CurrencyHandler) SetExchangeRate(c *gin.Context) {
+	var req SetExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exchange rate data: " + err.Error()})
+		return
+	}
+
+	rate, err := h.currencyService.SetRate(c.Param("currency"), req.Rate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rate)
+}
+
+func (h *CurrencyHandler) DeleteExchangeRate(c *gin.Context) {
+	if err := h.currencyService.DeleteRate(c.Param("currency")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Exchange rate deleted"})
+}
+
+// displayConverter разбирает ?currency=. При неизвестной валюте отвечает 400
+// и возвращает false.
+func displayConverter(c *gin.Context, currencyService services.CurrencyService) (*money.Converter, bool) {
+	conv, err := currencyService.Converter(c.Query("currency"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return conv, true
+}
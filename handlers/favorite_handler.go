@@ -71,4 +71,26 @@ func (h *FavoriteHandler) CheckFavorite(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"is_favorite": isFavorite})
-}
\ No newline at end of file
+}
+
+// SetAlerts включает уведомления о снижении цены и поступлении товара.
+func (h *FavoriteHandler) SetAlerts(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var input services.FavoriteAlertsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert settings: " + err.Error()})
+		return
+	}
+
+	if err := h.favoriteService.SetAlerts(userID, uint(productID), input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Alert settings updated"})
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IngredientHandler struct {
+	ingredientService services.IngredientService
+}
+
+func NewIngredientHandler(ingredientService services.IngredientService) *IngredientHandler {
+	return &IngredientHandler{ingredientService: ingredientService}
+}
+
+// SetProductIngredientsRequest — состав задаётся либо списком id, либо
+// текстом с упаковки (inci); неизвестные ингредиенты из текста добавляются.
+type SetProductIngredientsRequest struct {
+	IngredientIDs []uint `json:"ingredient_ids"`
+	INCI          string `json:"inci"`
+}
+
+func (h *IngredientHandler) GetIngredients(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	ingredients, total, err := h.ingredientService.ListIngredients(c.Query("search"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get ingredients"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ingredients": ingredients,
+		"total":       total,
+		"page":        page,
+		"limit":       limit,
+		"flags":       models.IngredientFlags,
+	})
+}
+
+func (h *IngredientHandler) GetIngredient(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ingredient ID"})
+		return
+	}
+
+	ingredient, err := h.ingredientService.GetIngredient(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, ingredient)
+}
+
+func (h *IngredientHandler) CreateIngredient(c *gin.Context) {
+	var ingredient models.Ingredient
+	if err := c.ShouldBindJSON(&ingredient); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.ingredientService.CreateIngredient(&ingredient); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, ingredient)
+}
+
+func (h *IngredientHandler) UpdateIngredient(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ingredient ID"})
+		return
+	}
+
+	var ingredient models.Ingredient
+	if err := c.ShouldBindJSON(&ingredient); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.ingredientService.UpdateIngredient(uint(id), &ingredient)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *IngredientHandler) DeleteIngredient(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ingredient ID"})
+		return
+	}
+
+	if err := h.ingredientService.DeleteIngredient(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Ingredient deleted successfully"})
+}
+
+// ImportIngredients принимает CSV в поле "file".
+func (h *IngredientHandler) ImportIngredients(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV file is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	result, err := h.ingredientService.ImportCSV(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "result": result})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *IngredientHandler) GetProductIngredients(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	composition, err := h.ingredientService.GetProductComposition(uint(productID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, composition)
+}
+
+func (h *IngredientHandler) SetProductIngredients(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req SetProductIngredientsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var composition *services.ProductComposition
+	if req.INCI != "" {
+		composition, err = h.ingredientService.SetProductINCI(uint(productID), req.INCI)
+	} else {
+		composition, err = h.ingredientService.SetProductIngredients(uint(productID), req.IngredientIDs)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, composition)
+}
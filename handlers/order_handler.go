@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
+	"1kosmetika-marketplace-backend/repositories"
 	"1kosmetika-marketplace-backend/services"
 	"1kosmetika-marketplace-backend/utils"
+	"errors"
 	"fmt"
+	"html"
 	"net/http"
 	"strconv"
 
@@ -11,15 +16,31 @@ import (
 )
 
 type OrderHandler struct {
-	orderService services.OrderService
+	orderService   services.OrderService
+	invoiceService services.InvoiceService
 }
 
-func NewOrderHandler(orderService services.OrderService) *OrderHandler {
-	return &OrderHandler{orderService: orderService}
+func NewOrderHandler(orderService services.OrderService, invoiceService services.InvoiceService) *OrderHandler {
+	return &OrderHandler{orderService: orderService, invoiceService: invoiceService}
+}
+
+// checkoutErrorStatus: нехватка остатка — 409, ошибки базы (сервис оборачивает
+// их через %w) — 500, остальное — неверные данные заказа.
+func checkoutErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repositories.ErrInsufficientStock):
+		return http.StatusConflict
+	case errors.Unwrap(err) != nil:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
 }
 
 type CreateOrderRequest struct {
-	ProductIDs []uint `json:"product_ids" binding:"required"`
+	ProductIDs       []uint `json:"product_ids" binding:"required"`
+	AddressID        *uint  `json:"address_id"`
+	ShippingMethodID *uint  `json:"shipping_method_id"`
 }
 
 func (h *OrderHandler) CreateOrder(c *gin.Context) {
@@ -35,24 +56,76 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	}
 
 	userID := c.GetUint("user_id")
-	order, err := h.orderService.CreateOrder(userID, req.ProductIDs)
+	order, err := h.orderService.CreateOrder(userID, services.CreateOrderInput{
+		ProductIDs:       req.ProductIDs,
+		AddressID:        req.AddressID,
+		ShippingMethodID: req.ShippingMethodID,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(checkoutErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
-	userEmail := c.GetString("user_email") // если ты сохраняешь email в токене
-	if userEmail == "" {
-		userEmail = "test@example.com" // временно, чтобы проверить работу
-	}
+	userEmail := c.GetString("user_email")
 
 	subject := "Ваш заказ успешно создан!"
 	body := fmt.Sprintf(`
 	<h2>Здравствуйте!</h2>
 	<p>Ваш заказ #%d успешно оформлен и ожидает подтверждения.</p>
-	<p>Общая сумма заказа: <b>%.2f</b></p>
-`, order.ID, order.Total)
+	%s
+`, order.ID, orderTotalsHTML(order))
+
+	if userEmail != "" {
+		if err := utils.SendEmailWithAttachments(userEmail, subject, body, h.invoiceAttachments(order.ID)); err != nil {
+			fmt.Println("❌ Ошибка при отправке email:", err)
+		}
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Order created successfully",
+		"order":   order,
+	})
+}
+
+type GuestCheckoutRequest struct {
+	Email            string `json:"email" binding:"required,email"`
+	FullName         string `json:"full_name" binding:"required"`
+	Phone            string `json:"phone" binding:"required"`
+	Region           string `json:"region"`
+	City             string `json:"city" binding:"required"`
+	Address          string `json:"address" binding:"required"`
+	PostalCode       string `json:"postal_code"`
+	ShippingMethodID *uint  `json:"shipping_method_id"`
+}
+
+func (h *OrderHandler) GuestCheckout(c *gin.Context) {
+	var req GuestCheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid checkout data: " + err.Error()})
+		return
+	}
+
+	order, err := h.orderService.CreateGuestOrder(c.GetString("guest_token"), services.GuestCheckoutInput{
+		Email:            req.Email,
+		FullName:         req.FullName,
+		Phone:            req.Phone,
+		Region:           req.Region,
+		City:             req.City,
+		Address:          req.Address,
+		PostalCode:       req.PostalCode,
+		ShippingMethodID: req.ShippingMethodID,
+	})
+	if err != nil {
+		c.JSON(checkoutErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	subject := "Ваш заказ успешно создан!"
+	body := fmt.Sprintf(`
+	<h2>Здравствуйте, %s!</h2>
+	<p>Ваш заказ #%d успешно оформлен и ожидает подтверждения.</p>
+	%s
+`, html.EscapeString(req.FullName), order.ID, orderTotalsHTML(order))
 
-	if err := utils.SendEmail(userEmail, subject, body); err != nil {
+	if err := utils.SendEmailWithAttachments(req.Email, subject, body, h.invoiceAttachments(order.ID)); err != nil {
 		fmt.Println("❌ Ошибка при отправке email:", err)
 	}
 	c.JSON(http.StatusCreated, gin.H{
@@ -61,6 +134,39 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	})
 }
 
+// Счёт прикладывается к письму о заказе; без него письмо всё равно уходит.
+func (h *OrderHandler) invoiceAttachments(orderID uint) []utils.EmailAttachment {
+	invoice, err := h.invoiceService.GenerateInvoice(orderID)
+	if err != nil {
+		fmt.Println("❌ Ошибка при создании счёта:", err)
+		return nil
+	}
+	return []utils.EmailAttachment{{
+		Filename:    "invoice-" + invoice.Number + ".pdf",
+		ContentType: "application/pdf",
+		Data:        invoice.PDF,
+	}}
+}
+
+// Суммы в письме: товары, налог и доставка отдельно, если они есть.
+func orderTotalsHTML(order *models.Order) string {
+	if order.ShippingMethodID == nil && order.TaxTotal == 0 {
+		return fmt.Sprintf("<p>Общая сумма заказа: <b>%s</b></p>", money.New(order.Total, order.Currency))
+	}
+	out := fmt.Sprintf("<p>Товары: %s</p>\n", order.Subtotal)
+	if order.TaxTotal > 0 {
+		label := "Налог"
+		if order.PricesIncludeTax {
+			label = "В том числе налог"
+		}
+		out += fmt.Sprintf("\t<p>%s: %s</p>\n", label, order.TaxTotal)
+	}
+	if order.ShippingMethodID != nil {
+		out += fmt.Sprintf("\t<p>Доставка (%s): %s</p>\n", html.EscapeString(order.ShippingMethodName), order.ShippingCost)
+	}
+	return out + fmt.Sprintf("\t<p>Общая сумма заказа: <b>%s</b></p>", money.New(order.Total, order.Currency))
+}
+
 func (h *OrderHandler) GetUserOrders(c *gin.Context) {
 	userID := c.GetUint("user_id")
 	orders, err := h.orderService.GetUserOrders(userID)
@@ -79,7 +185,9 @@ func (h *OrderHandler) GetOrderByID(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.GetOrderByID(uint(orderID))
+	userID := c.GetUint("user_id")
+	isAdmin := c.GetString("role") == "admin"
+	order, err := h.orderService.GetOrderByID(uint(orderID), userID, isAdmin)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
 		return
@@ -87,6 +195,54 @@ func (h *OrderHandler) GetOrderByID(c *gin.Context) {
 	c.JSON(http.StatusOK, order)
 }
 
+func (h *OrderHandler) GetInvoice(c *gin.Context) {
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	isAdmin := c.GetString("role") == "admin"
+	invoice, err := h.invoiceService.GetInvoice(uint(orderID), userID, isAdmin)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="invoice-`+invoice.Number+`.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", invoice.PDF)
+}
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+func (h *OrderHandler) CancelOrder(c *gin.Context) {
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var req CancelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cancellation data: " + err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	order, err := h.orderService.CancelOrder(uint(orderID), userID, req.Reason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Order cancelled",
+		"order":   order,
+	})
+}
+
 func (h *OrderHandler) GetAllOrders(c *gin.Context) {
 	orders, err := h.orderService.GetAllOrders()
 	if err != nil {
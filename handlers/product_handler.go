@@ -2,32 +2,100 @@ package handlers
 
 import (
 	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
 	"1kosmetika-marketplace-backend/repositories"
 	"1kosmetika-marketplace-backend/services"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ProductHandler struct {
-	productService services.ProductService
+	productService       services.ProductService
+	currencyService      services.CurrencyService
+	recentViewService    services.RecentViewService
+	beautyProfileService services.BeautyProfileService
+	ingredientService    services.IngredientService
 }
 
-func NewProductHandler(productService services.ProductService) *ProductHandler {
-	return &ProductHandler{productService: productService}
+func NewProductHandler(
+	productService services.ProductService,
+	currencyService services.CurrencyService,
+	recentViewService services.RecentViewService,
+	beautyProfileService services.BeautyProfileService,
+	ingredientService services.IngredientService,
+) *ProductHandler {
+	return &ProductHandler{
+		productService:       productService,
+		currencyService:      currencyService,
+		recentViewService:    recentViewService,
+		beautyProfileService: beautyProfileService,
+		ingredientService:    ingredientService,
+	}
+}
+
+// beautyProfile — профиль авторизованного покупателя или nil.
+func (h *ProductHandler) beautyProfile(c *gin.Context) *models.BeautyProfile {
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		return nil
+	}
+	profile, err := h.beautyProfileService.GetProfile(userID)
+	if err != nil {
+		return nil
+	}
+	return profile
+}
+
+// flagAllergens помечает аллергены профиля в товарах. Составы грузятся одним
+// запросом; если он не удался, сверяется только текст состава.
+func (h *ProductHandler) flagAllergens(profile *models.BeautyProfile, products ...*models.Product) {
+	if profile == nil || len(profile.Allergies) == 0 || len(products) == 0 {
+		return
+	}
+	ids := make([]uint, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+	compositions, err := h.ingredientService.GetProductsIngredients(ids)
+	if err != nil {
+		log.Printf("❌ Failed to load product ingredients: %v", err)
+	}
+	for _, p := range products {
+		p.FlagAllergens(profile.Allergies, compositions[p.ID])
+	}
+}
+
+func applyCurrency(products []models.Product, conv *money.Converter) {
+	for i := range products {
+		products[i].ApplyCurrency(conv)
+	}
 }
 
 func (h *ProductHandler) GetProducts(c *gin.Context) {
+	conv, ok := displayConverter(c, h.currencyService)
+	if !ok {
+		return
+	}
+
 	products, err := h.productService.GetAllProducts()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get products"})
 		return
 	}
+	applyCurrency(products, conv)
 	c.JSON(http.StatusOK, products)
 }
 
 func (h *ProductHandler) GetProductsPaginated(c *gin.Context) {
+	conv, ok := displayConverter(c, h.currencyService)
+	if !ok {
+		return
+	}
+
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 
@@ -36,6 +104,7 @@ func (h *ProductHandler) GetProductsPaginated(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get products"})
 		return
 	}
+	applyCurrency(products, conv)
 
 	c.JSON(http.StatusOK, gin.H{
 		"products": products,
@@ -47,21 +116,61 @@ func (h *ProductHandler) GetProductsPaginated(c *gin.Context) {
 }
 
 func (h *ProductHandler) SearchProducts(c *gin.Context) {
+	conv, ok := displayConverter(c, h.currencyService)
+	if !ok {
+		return
+	}
 
 	filter := repositories.ProductFilter{
 		Category: c.Query("category"),
 		Brand:    c.Query("brand"),
 		Search:   c.Query("search"),
+		Sort:     c.Query("sort"),
+		SkinType: c.Query("skin_type"),
+		HairType: c.Query("hair_type"),
+		Concern:  c.Query("concern"),
+	}
+	filter.IncludeIngredients = splitQueryList(c.Query("ingredients"))
+	filter.ExcludeIngredients = splitQueryList(c.Query("exclude_ingredients"))
+	filter.FreeFrom = splitQueryList(c.Query("free_from"))
+	for _, flag := range filter.FreeFrom {
+		if !isIngredientFlag(flag) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "free_from must be a list of: " + strings.Join(models.IngredientFlags, ", ")})
+			return
+		}
+	}
+
+	// for_me=true — только подходящие по профилю товары без аллергенов из него.
+	profile := h.beautyProfile(c)
+	if c.Query("for_me") == "true" {
+		if profile == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Fill in your beauty profile to see products suitable for you"})
+			return
+		}
+		if filter.SkinType == "" {
+			filter.SkinType = profile.SkinType
+		}
+		if filter.HairType == "" {
+			filter.HairType = profile.HairType
+		}
+		filter.ExcludeAllergens = profile.Allergies
 	}
+	filter.Profile = profile
 
+	// Границы цены вводятся в валюте отображения, а цены хранятся в базовой.
 	if minPriceStr := c.Query("min_price"); minPriceStr != "" {
-		if minPrice, err := strconv.ParseFloat(minPriceStr, 64); err == nil {
-			filter.MinPrice = minPrice
+		if minPrice, err := money.Parse(minPriceStr); err == nil {
+			filter.MinPrice = conv.ToBase(minPrice)
 		}
 	}
 	if maxPriceStr := c.Query("max_price"); maxPriceStr != "" {
-		if maxPrice, err := strconv.ParseFloat(maxPriceStr, 64); err == nil {
-			filter.MaxPrice = maxPrice
+		if maxPrice, err := money.Parse(maxPriceStr); err == nil {
+			filter.MaxPrice = conv.ToBase(maxPrice)
+		}
+	}
+	if minRatingStr := c.Query("min_rating"); minRatingStr != "" {
+		if minRating, err := strconv.ParseFloat(minRatingStr, 64); err == nil {
+			filter.MinRating = minRating
 		}
 	}
 
@@ -73,6 +182,12 @@ func (h *ProductHandler) SearchProducts(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search products"})
 		return
 	}
+	applyCurrency(products, conv)
+	flagged := make([]*models.Product, len(products))
+	for i := range products {
+		flagged[i] = &products[i]
+	}
+	h.flagAllergens(profile, flagged...)
 
 	c.JSON(http.StatusOK, gin.H{
 		"products":  products,
@@ -85,6 +200,26 @@ func (h *ProductHandler) SearchProducts(c *gin.Context) {
 }
 
 
+// splitQueryList разбирает параметр вида "a,b,c".
+func splitQueryList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func isIngredientFlag(flag string) bool {
+	for _, f := range models.IngredientFlags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
 func (h *ProductHandler) GetCategories(c *gin.Context) {
 	categories, err := h.productService.GetCategories()
 	if err != nil {
@@ -111,15 +246,119 @@ func (h *ProductHandler) GetProductByID(c *gin.Context) {
 		return
 	}
 
+	conv, ok := displayConverter(c, h.currencyService)
+	if !ok {
+		return
+	}
+
 	product, err := h.productService.GetProductByID(uint(productID))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
 		return
 	}
+	h.recentViewService.RecordView(product.ID, c.GetUint("user_id"))
+	h.flagAllergens(h.beautyProfile(c), product)
+	product.ApplyCurrency(conv)
+	c.JSON(http.StatusOK, product)
+}
+
+func (h *ProductHandler) GetProductBySlug(c *gin.Context) {
+	conv, ok := displayConverter(c, h.currencyService)
+	if !ok {
+		return
+	}
+
+	product, redirected, err := h.productService.GetProductBySlug(c.Param("slug"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+	if redirected {
+		target := "/api/products/slug/" + product.Slug
+		if c.Request.URL.RawQuery != "" {
+			target += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, target)
+		return
+	}
+	h.recentViewService.RecordView(product.ID, c.GetUint("user_id"))
+	h.flagAllergens(h.beautyProfile(c), product)
+	product.ApplyCurrency(conv)
+	c.JSON(http.StatusOK, product)
+}
+func (h *ProductHandler) GetPriceHistory(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	history, err := h.productService.GetPriceHistory(uint(productID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *ProductHandler) GetAdminProducts(c *gin.Context) {
+	filter := repositories.ProductFilter{
+		Category: c.Query("category"),
+		Brand:    c.Query("brand"),
+		Search:   c.Query("search"),
+		Status:   c.DefaultQuery("status", "all"),
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	products, total, err := h.productService.GetProductsForAdmin(filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get products"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"products": products,
+		"total":    total,
+		"page":     page,
+		"limit":    limit,
+		"pages":    (total + int64(limit) - 1) / int64(limit),
+		"filters":  filter,
+	})
+}
+
+func (h *ProductHandler) GetAdminProductByID(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	product, err := h.productService.GetProductForAdmin(uint(productID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
 	c.JSON(http.StatusOK, product)
 }
 
 
+// saveINCI размечает состав, присланный вместе с карточкой, так же как
+// PUT /api/products/:id/ingredients. Пустой состав разметку не стирает.
+func (h *ProductHandler) saveINCI(c *gin.Context, productID uint, text string, product *models.Product) bool {
+	if strings.TrimSpace(text) == "" {
+		return true
+	}
+	composition, err := h.ingredientService.SetProductINCI(productID, text)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Product saved, but ingredients were not: " + err.Error()})
+		return false
+	}
+	product.Ingredients = composition.Text
+	return true
+}
+
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	var product models.Product
 	if err := c.ShouldBindJSON(&product); err != nil {
@@ -127,10 +366,14 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		return
 	}
 
+	inci := product.Ingredients
 	if err := h.productService.CreateProduct(&product); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !h.saveINCI(c, product.ID, inci, &product) {
+		return
+	}
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Product created successfully",
@@ -156,6 +399,9 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !h.saveINCI(c, uint(productID), product.Ingredients, &product) {
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Product updated successfully",
@@ -177,4 +423,28 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Product deleted successfully"})
-}
\ No newline at end of file
+}
+type RestockRequest struct {
+	Quantity int `json:"quantity" binding:"required,min=1"`
+}
+
+func (h *ProductHandler) RestockProduct(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req RestockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restock data: " + err.Error()})
+		return
+	}
+
+	product, err := h.productService.Restock(uint(productID), req.Quantity)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, product)
+}
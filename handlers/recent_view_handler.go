@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"1kosmetika-marketplace-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecentViewHandler struct {
+	recentViewService services.RecentViewService
+	currencyService   services.CurrencyService
+}
+
+func NewRecentViewHandler(recentViewService services.RecentViewService, currencyService services.CurrencyService) *RecentViewHandler {
+	return &RecentViewHandler{
+		recentViewService: recentViewService,
+		currencyService:   currencyService,
+	}
+}
+
+func (h *RecentViewHandler) GetRecentlyViewed(c *gin.Context) {
+	conv, ok := displayConverter(c, h.currencyService)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	products, err := h.recentViewService.GetRecentlyViewed(c.GetUint("user_id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get recently viewed products"})
+		return
+	}
+	applyCurrency(products, conv)
+	c.JSON(http.StatusOK, products)
+}
+
+func (h *RecentViewHandler) ClearRecentlyViewed(c *gin.Context) {
+	if err := h.recentViewService.ClearRecentlyViewed(c.GetUint("user_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear recently viewed products"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Recently viewed products cleared"})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"1kosmetika-marketplace-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecommendationHandler struct {
+	recommendationService services.RecommendationService
+	currencyService       services.CurrencyService
+}
+
+func NewRecommendationHandler(recommendationService services.RecommendationService, currencyService services.CurrencyService) *RecommendationHandler {
+	return &RecommendationHandler{
+		recommendationService: recommendationService,
+		currencyService:       currencyService,
+	}
+}
+
+func (h *RecommendationHandler) GetRelatedProducts(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+	conv, ok := displayConverter(c, h.currencyService)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "8"))
+	products, err := h.recommendationService.GetRelated(uint(productID), limit)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	applyCurrency(products, conv)
+	c.JSON(http.StatusOK, products)
+}
+
+func (h *RecommendationHandler) GetRecommendations(c *gin.Context) {
+	conv, ok := displayConverter(c, h.currencyService)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "12"))
+	products, err := h.recommendationService.GetForUser(c.GetUint("user_id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get recommendations"})
+		return
+	}
+	applyCurrency(products, conv)
+	c.JSON(http.StatusOK, products)
+}
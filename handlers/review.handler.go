@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"1kosmetika-marketplace-backend/repositories"
 	"1kosmetika-marketplace-backend/services"
 	"net/http"
 	"strconv"
@@ -17,16 +18,38 @@ func NewReviewHandler(reviewService services.ReviewService) *ReviewHandler {
 }
 
 type CreateReviewRequest struct {
-	ProductID uint   `json:"product_id" binding:"required"`
-	Rating    int    `json:"rating" binding:"required,min=1,max=5"`
-	Comment   string `json:"comment" binding:"max=500"`
+	ProductID uint     `json:"product_id" binding:"required"`
+	Rating    int      `json:"rating" binding:"required,min=1,max=5"`
+	Comment   string   `json:"comment" binding:"max=500"`
+	Photos    []string `json:"photos"`
 }
 
 type UpdateReviewRequest struct {
-	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Rating  int      `json:"rating" binding:"required,min=1,max=5"`
+	Comment string   `json:"comment" binding:"max=500"`
+	Photos  []string `json:"photos"` // не передано — фото остаются прежними
+}
+
+type VoteReviewRequest struct {
+	Helpful *bool `json:"helpful" binding:"required"`
+}
+
+type ReviewReplyRequest struct {
+	Body string `json:"body" binding:"required,max=2000"`
+}
+
+type ReportReviewRequest struct {
+	Reason  string `json:"reason" binding:"required"`
 	Comment string `json:"comment" binding:"max=500"`
 }
 
+type ModerateReviewRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+	Note   string `json:"note" binding:"max=500"`
+}
+
+// GetProductReviews: ?sort=newest|helpful|highest|lowest, ?rating=1..5,
+// ?with_photos=true, ?page, ?limit.
 func (h *ReviewHandler) GetProductReviews(c *gin.Context) {
 	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
 	if err != nil {
@@ -34,17 +57,28 @@ func (h *ReviewHandler) GetProductReviews(c *gin.Context) {
 		return
 	}
 
-	reviews, avgRating, totalCount, err := h.reviewService.GetProductReviews(uint(productID))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	rating, _ := strconv.Atoi(c.Query("rating"))
+	if rating < 0 || rating > 5 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "rating filter must be between 1 and 5"})
+		return
+	}
+	query := repositories.ReviewQuery{
+		Rating:     rating,
+		WithPhotos: c.Query("with_photos") == "true",
+		Sort:       c.DefaultQuery("sort", repositories.ReviewSortNewest),
+		Page:       page,
+		Limit:      limit,
+	}
+
+	result, err := h.reviewService.GetProductReviews(uint(productID), query)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get reviews"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"reviews":     reviews,
-		"avg_rating":  avgRating,
-		"total_count": totalCount,
-	})
+	c.JSON(http.StatusOK, result)
 }
 
 func (h *ReviewHandler) GetUserReviews(c *gin.Context) {
@@ -69,7 +103,7 @@ func (h *ReviewHandler) CreateReview(c *gin.Context) {
 		return
 	}
 
-	review, err := h.reviewService.CreateReview(userID, req.ProductID, req.Rating, req.Comment)
+	review, err := h.reviewService.CreateReview(userID, req.ProductID, req.Rating, req.Comment, req.Photos)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -92,7 +126,7 @@ func (h *ReviewHandler) UpdateReview(c *gin.Context) {
 		return
 	}
 
-	review, err := h.reviewService.UpdateReview(uint(reviewID), userID, req.Rating, req.Comment)
+	review, err := h.reviewService.UpdateReview(uint(reviewID), userID, req.Rating, req.Comment, req.Photos)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -116,4 +150,155 @@ func (h *ReviewHandler) DeleteReview(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Review deleted successfully"})
-}
\ No newline at end of file
+}
+
+// GetModerationQueue — отзывы по статусу модерации (по умолчанию pending).
+func (h *ReviewHandler) GetModerationQueue(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	reviews, total, err := h.reviewService.GetModerationQueue(c.Query("status"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviews": reviews,
+		"total":   total,
+		"page":    page,
+		"limit":   limit,
+	})
+}
+
+func (h *ReviewHandler) ModerateReview(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	var req ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.reviewService.ModerateReview(uint(reviewID), req.Status, req.Note)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+func (h *ReviewHandler) VoteReview(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	var req VoteReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.reviewService.VoteReview(uint(reviewID), c.GetUint("user_id"), *req.Helpful)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+func (h *ReviewHandler) RemoveVote(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	review, err := h.reviewService.RemoveVote(uint(reviewID), c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+func (h *ReviewHandler) ReportReview(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	var req ReportReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.reviewService.ReportReview(uint(reviewID), c.GetUint("user_id"), req.Reason, req.Comment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Report submitted"})
+}
+
+func (h *ReviewHandler) ReplyToReview(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	var req ReviewReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reply, err := h.reviewService.ReplyToReview(uint(reviewID), c.GetUint("user_id"), req.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, reply)
+}
+
+func (h *ReviewHandler) DeleteReply(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+	replyID, err := strconv.ParseUint(c.Param("replyId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reply ID"})
+		return
+	}
+
+	if err := h.reviewService.DeleteReply(uint(reviewID), uint(replyID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Reply deleted"})
+}
+
+func (h *ReviewHandler) GetReviewReports(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	reports, err := h.reviewService.GetReviewReports(uint(reviewID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get reports"})
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
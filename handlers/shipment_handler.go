@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"1kosmetika-marketplace-backend/carriers"
+	"1kosmetika-marketplace-backend/services"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const carrierSignatureHeader = "X-Carrier-Signature"
+
+type ShipmentHandler struct {
+	shipmentService services.ShipmentService
+	webhookSecret   string
+}
+
+func NewShipmentHandler(shipmentService services.ShipmentService, webhookSecret string) *ShipmentHandler {
+	return &ShipmentHandler{shipmentService: shipmentService, webhookSecret: webhookSecret}
+}
+
+func (h *ShipmentHandler) GetOrderTracking(c *gin.Context) {
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	isAdmin := c.GetString("role") == "admin"
+	shipments, err := h.shipmentService.GetOrderTracking(uint(orderID), userID, isAdmin)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"order_id":  orderID,
+		"shipments": shipments,
+	})
+}
+
+type CreateShipmentRequest struct {
+	Carrier        string `json:"carrier" binding:"required"`
+	TrackingNumber string `json:"tracking_number" binding:"required"`
+}
+
+func (h *ShipmentHandler) CreateShipment(c *gin.Context) {
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var req CreateShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipment data: " + err.Error()})
+		return
+	}
+
+	shipment, err := h.shipmentService.CreateShipment(uint(orderID), req.Carrier, req.TrackingNumber)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, shipment)
+}
+
+type ShipmentEventRequest struct {
+	Status      string    `json:"status" binding:"required"`
+	Description string    `json:"description"`
+	Location    string    `json:"location"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+func (h *ShipmentHandler) AddShipmentEvent(c *gin.Context) {
+	shipmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipment ID"})
+		return
+	}
+
+	var req ShipmentEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event data: " + err.Error()})
+		return
+	}
+
+	shipment, err := h.shipmentService.AddEvent(uint(shipmentID), carriers.TrackingEvent{
+		Status:      req.Status,
+		Description: req.Description,
+		Location:    req.Location,
+		OccurredAt:  req.OccurredAt,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, shipment)
+}
+
+type carrierWebhookPayload struct {
+	TrackingNumber string                   `json:"tracking_number"`
+	Events         []carriers.TrackingEvent `json:"events"`
+}
+
+// CarrierWebhook принимает события от курьерской службы. Тело запроса
+// подписывается HMAC-SHA256 общим секретом (hex в X-Carrier-Signature).
+func (h *ShipmentHandler) CarrierWebhook(c *gin.Context) {
+	if h.webhookSecret == "" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Webhooks are disabled"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.webhookSecret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(c.GetHeader(carrierSignatureHeader))) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
+		return
+	}
+
+	var payload carrierWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil || payload.TrackingNumber == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook payload"})
+		return
+	}
+
+	if err := h.shipmentService.HandleWebhook(c.Param("carrier"), payload.TrackingNumber, payload.Events); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Events accepted"})
+}
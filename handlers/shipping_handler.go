@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
+	"1kosmetika-marketplace-backend/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShippingHandler struct {
+	shippingService services.ShippingService
+}
+
+func NewShippingHandler(shippingService services.ShippingService) *ShippingHandler {
+	return &ShippingHandler{shippingService: shippingService}
+}
+
+// GetShippingOptions без region возвращает все активные способы с тарифами,
+// с region — только доступные по адресу способы с рассчитанной ценой.
+func (h *ShippingHandler) GetShippingOptions(c *gin.Context) {
+	region := c.Query("region")
+	if region == "" {
+		methods, err := h.shippingService.GetMethods(true)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get shipping methods"})
+			return
+		}
+		c.JSON(http.StatusOK, methods)
+		return
+	}
+
+	subtotal, _ := money.Parse(c.DefaultQuery("subtotal", "0"))
+	quotes, err := h.shippingService.QuoteAll(region, c.Query("city"), subtotal)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get shipping options"})
+		return
+	}
+	c.JSON(http.StatusOK, quotes)
+}
+
+func (h *ShippingHandler) GetAllMethods(c *gin.Context) {
+	methods, err := h.shippingService.GetMethods(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get shipping methods"})
+		return
+	}
+	c.JSON(http.StatusOK, methods)
+}
+
+func (h *ShippingHandler) CreateMethod(c *gin.Context) {
+	// Без is_active в запросе способ создаётся активным; явный false сохраняется.
+	method := models.ShippingMethod{IsActive: true}
+	if err := c.ShouldBindJSON(&method); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipping method data: " + err.Error()})
+		return
+	}
+
+	if err := h.shippingService.CreateMethod(&method); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, method)
+}
+
+func (h *ShippingHandler) UpdateMethod(c *gin.Context) {
+	methodID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipping method ID"})
+		return
+	}
+
+	var input models.ShippingMethod
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipping method data: " + err.Error()})
+		return
+	}
+
+	method, err := h.shippingService.UpdateMethod(uint(methodID), &input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, method)
+}
+
+func (h *ShippingHandler) DeleteMethod(c *gin.Context) {
+	methodID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipping method ID"})
+		return
+	}
+
+	if err := h.shippingService.DeleteMethod(uint(methodID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Shipping method deleted"})
+}
+
+func (h *ShippingHandler) AddRate(c *gin.Context) {
+	methodID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipping method ID"})
+		return
+	}
+
+	var rate models.ShippingRate
+	if err := c.ShouldBindJSON(&rate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipping rate data: " + err.Error()})
+		return
+	}
+
+	if err := h.shippingService.AddRate(uint(methodID), &rate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, rate)
+}
+
+func (h *ShippingHandler) UpdateRate(c *gin.Context) {
+	methodID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipping method ID"})
+		return
+	}
+	rateID, err := strconv.ParseUint(c.Param("rateId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipping rate ID"})
+		return
+	}
+
+	var input models.ShippingRate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipping rate data: " + err.Error()})
+		return
+	}
+
+	rate, err := h.shippingService.UpdateRate(uint(methodID), uint(rateID), &input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rate)
+}
+
+func (h *ShippingHandler) DeleteRate(c *gin.Context) {
+	methodID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipping method ID"})
+		return
+	}
+	rateID, err := strconv.ParseUint(c.Param("rateId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipping rate ID"})
+		return
+	}
+
+	if err := h.shippingService.DeleteRate(uint(methodID), uint(rateID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Shipping rate deleted"})
+}
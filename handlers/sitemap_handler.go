@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"time"
+
+	"1kosmetika-marketplace-backend/services"
+	"1kosmetika-marketplace-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SitemapHandler struct {
+	productService services.ProductService
+	siteURL        string
+}
+
+func NewSitemapHandler(productService services.ProductService, siteURL string) *SitemapHandler {
+	return &SitemapHandler{
+		productService: productService,
+		siteURL:        strings.TrimRight(siteURL, "/"),
+	}
+}
+
+type sitemapURL struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+func (h *SitemapHandler) GetSitemap(c *gin.Context) {
+	products, err := h.productService.GetAllProducts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build sitemap"})
+		return
+	}
+	categories, err := h.productService.GetCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build sitemap"})
+		return
+	}
+
+	urlSet := sitemapURLSet{
+		Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9",
+		URLs: []sitemapURL{
+			{Loc: h.siteURL + "/", ChangeFreq: "daily", Priority: "1.0"},
+		},
+	}
+
+	for _, category := range categories {
+		slug := utils.Slugify(category)
+		if slug == "" {
+			continue
+		}
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{
+			Loc:        h.siteURL + "/categories/" + slug,
+			ChangeFreq: "weekly",
+			Priority:   "0.8",
+		})
+	}
+
+	for _, product := range products {
+		if product.Slug == "" {
+			continue
+		}
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{
+			Loc:        h.siteURL + "/products/" + product.Slug,
+			LastMod:    product.UpdatedAt.Format(time.DateOnly),
+			ChangeFreq: "weekly",
+			Priority:   "0.6",
+		})
+	}
+
+	body, err := xml.MarshalIndent(urlSet, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build sitemap"})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", append([]byte(xml.Header), body...))
+}
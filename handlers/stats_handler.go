@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"1kosmetika-marketplace-backend/repositories"
 	"1kosmetika-marketplace-backend/services"
 	"net/http"
 	"strconv"
@@ -9,11 +10,12 @@ import (
 )
 
 type StatsHandler struct {
-	statsService *services.StatsService
+	statsService       *services.StatsService
+	abandonedCartHours int
 }
 
-func NewStatsHandler(statsService *services.StatsService) *StatsHandler {
-	return &StatsHandler{statsService: statsService}
+func NewStatsHandler(statsService *services.StatsService, abandonedCartHours int) *StatsHandler {
+	return &StatsHandler{statsService: statsService, abandonedCartHours: abandonedCartHours}
 }
 
 func (h *StatsHandler) GetAdminStats(c *gin.Context) {
@@ -41,7 +43,13 @@ func (h *StatsHandler) GetPopularProducts(c *gin.Context) {
 		limit = 10
 	}
 
-	products, err := h.statsService.GetPopularProducts(limit)
+	sortBy := c.DefaultQuery("sort", repositories.PopularBySales)
+	if sortBy != repositories.PopularBySales && sortBy != repositories.PopularByViews {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be sales or views"})
+		return
+	}
+
+	products, err := h.statsService.GetPopularProducts(limit, sortBy)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch popular products"})
 		return
@@ -150,3 +158,17 @@ func (h *StatsHandler) GetProfitStats(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, stats)
 }
+
+func (h *StatsHandler) GetAbandonedCartStats(c *gin.Context) {
+	hours, err := strconv.Atoi(c.Query("hours"))
+	if err != nil || hours <= 0 {
+		hours = h.abandonedCartHours
+	}
+
+	stats, err := h.statsService.GetAbandonedCartStats(hours)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch abandoned cart stats"})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"1kosmetika-marketplace-backend/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StockAlertHandler struct {
+	stockAlertService services.StockAlertService
+}
+
+func NewStockAlertHandler(stockAlertService services.StockAlertService) *StockAlertHandler {
+	return &StockAlertHandler{stockAlertService: stockAlertService}
+}
+
+type NotifyMeRequest struct {
+	Email string `json:"email"`
+}
+
+// NotifyMe подписывает на поступление товара. Гостю нужен email в теле,
+// пользователю — необязательно: берётся email из токена.
+func (h *StockAlertHandler) NotifyMe(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req NotifyMeRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid subscription data: " + err.Error()})
+		return
+	}
+
+	var userID *uint
+	if id := c.GetUint("user_id"); id != 0 {
+		userID = &id
+		if req.Email == "" {
+			req.Email = c.GetString("user_email")
+		}
+	}
+
+	if err := h.stockAlertService.Subscribe(uint(productID), userID, req.Email); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "We will notify you when the product is back in stock"})
+}
+
+func (h *StockAlertHandler) CancelNotifyMe(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	if err := h.stockAlertService.Unsubscribe(uint(productID), c.GetUint("user_id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Subscription cancelled"})
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaxHandler struct {
+	taxService services.TaxService
+}
+
+func NewTaxHandler(taxService services.TaxService) *TaxHandler {
+	return &TaxHandler{taxService: taxService}
+}
+
+func (h *TaxHandler) GetTaxRates(c *gin.Context) {
+	rates, err := h.taxService.GetTaxRates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tax rates"})
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
+
+func (h *TaxHandler) CreateTaxRate(c *gin.Context) {
+	// Без is_active в запросе ставка создаётся активной; явный false сохраняется.
+	rate := models.TaxRate{IsActive: true}
+	if err := c.ShouldBindJSON(&rate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tax rate data: " + err.Error()})
+		return
+	}
+
+	if err := h.taxService.CreateTaxRate(&rate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, rate)
+}
+
+func (h *TaxHandler) UpdateTaxRate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tax rate ID"})
+		return
+	}
+
+	var input models.TaxRate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tax rate data: " + err.Error()})
+		return
+	}
+
+	rate, err := h.taxService.UpdateTaxRate(uint(id), &input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rate)
+}
+
+func (h *TaxHandler) DeleteTaxRate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tax rate ID"})
+		return
+	}
+
+	if err := h.taxService.DeleteTaxRate(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Tax rate deleted"})
+}
@@ -13,6 +13,18 @@ import (
 
 
 func UploadProductImageHandler(c *gin.Context) {
+	saveUploadedImage(c, "products")
+}
+
+// UploadReviewPhotoHandler загружает фото к отзыву; полученный url
+// передаётся в поле photos при создании или изменении отзыва.
+func UploadReviewPhotoHandler(c *gin.Context) {
+	saveUploadedImage(c, "reviews")
+}
+
+// saveUploadedImage сохраняет изображение из поля "image" в uploads/<dir>,
+// откуда оно раздаётся как /static/<dir>/<файл>.
+func saveUploadedImage(c *gin.Context, dir string) {
 	file, err := c.FormFile("image")
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Не удалось получить файл"})
@@ -25,7 +37,7 @@ func UploadProductImageHandler(c *gin.Context) {
 		return
 	}
 
-	uploadDir := "uploads/products"
+	uploadDir := filepath.Join("uploads", dir)
 	if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании директории"})
 		return
@@ -39,7 +51,7 @@ func UploadProductImageHandler(c *gin.Context) {
 		return
 	}
 
-	fileURL := "/static/products/" + filename
+	fileURL := "/static/" + dir + "/" + filename
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Файл успешно загружен ✅",
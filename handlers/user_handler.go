@@ -3,6 +3,8 @@ package handlers
 import (
 	"1kosmetika-marketplace-backend/models"
 	"1kosmetika-marketplace-backend/services"
+	"1kosmetika-marketplace-backend/utils"
+	"log"
 	"net/http"
 	"strconv"
 
@@ -11,10 +13,28 @@ import (
 
 type UserHandler struct {
 	userService services.UserService
+	cartService services.CartService
 }
 
-func NewUserHandler(userService services.UserService) *UserHandler {
-	return &UserHandler{userService: userService}
+func NewUserHandler(userService services.UserService, cartService services.CartService) *UserHandler {
+	return &UserHandler{userService: userService, cartService: cartService}
+}
+
+// mergeGuestCart переносит гостевую корзину (если есть) в корзину пользователя
+// после входа или регистрации и удаляет гостевой cookie.
+func (h *UserHandler) mergeGuestCart(c *gin.Context, userID uint) *services.CartSummary {
+	token, ok := utils.GuestTokenFromRequest(c)
+	if !ok {
+		return nil
+	}
+
+	cart, err := h.cartService.MergeGuestCart(token, userID)
+	if err != nil {
+		log.Printf("❌ Failed to merge guest cart for user %d: %v", userID, err)
+		return nil
+	}
+	c.SetCookie(utils.GuestCookieName, "", -1, "/", "", false, true)
+	return cart
 }
 
 type RegisterRequest struct {
@@ -57,10 +77,15 @@ func (h *UserHandler) Register(c *gin.Context) {
 		"role":      user.Role,
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	response := gin.H{
 		"message": "User registered successfully",
 		"user":    userResponse,
-	})
+	}
+	if cart := h.mergeGuestCart(c, user.ID); cart != nil {
+		response["cart"] = cart
+	}
+
+	c.JSON(http.StatusCreated, response)
 }
 
 func (h *UserHandler) Login(c *gin.Context) {
@@ -76,7 +101,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"message": "Login successful",
 		"token":   token,
 		"user": gin.H{
@@ -85,7 +110,12 @@ func (h *UserHandler) Login(c *gin.Context) {
 			"email":     user.Email,
 			"role":      user.Role,
 		},
-	})
+	}
+	if cart := h.mergeGuestCart(c, user.ID); cart != nil {
+		response["cart"] = cart
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 func (h *UserHandler) GetProfile(c *gin.Context) {
@@ -103,6 +133,32 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 		"email":      user.Email,
 		"role":       user.Role,
 		"created_at": user.CreatedAt,
+
+		"cart_reminders": !user.CartRemindersOptOut,
+	})
+}
+
+type UpdatePreferencesRequest struct {
+	CartReminders *bool `json:"cart_reminders" binding:"required"`
+}
+
+func (h *UserHandler) UpdatePreferences(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	var req UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.userService.UpdatePreferences(userID, *req.CartReminders)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Preferences updated successfully",
+		"cart_reminders": !user.CartRemindersOptOut,
 	})
 }
 
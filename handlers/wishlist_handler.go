@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"1kosmetika-marketplace-backend/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WishlistHandler struct {
+	wishlistService services.WishlistService
+}
+
+func NewWishlistHandler(wishlistService services.WishlistService) *WishlistHandler {
+	return &WishlistHandler{wishlistService: wishlistService}
+}
+
+type WishlistRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+func wishlistIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wishlist ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *WishlistHandler) GetWishlists(c *gin.Context) {
+	wishlists, err := h.wishlistService.GetWishlists(c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wishlists"})
+		return
+	}
+	c.JSON(http.StatusOK, wishlists)
+}
+
+func (h *WishlistHandler) GetWishlist(c *gin.Context) {
+	id, ok := wishlistIDParam(c)
+	if !ok {
+		return
+	}
+	wishlist, err := h.wishlistService.GetWishlist(c.GetUint("user_id"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, wishlist)
+}
+
+func (h *WishlistHandler) CreateWishlist(c *gin.Context) {
+	var req WishlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wishlist data: " + err.Error()})
+		return
+	}
+	wishlist, err := h.wishlistService.CreateWishlist(c.GetUint("user_id"), req.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, wishlist)
+}
+
+func (h *WishlistHandler) RenameWishlist(c *gin.Context) {
+	id, ok := wishlistIDParam(c)
+	if !ok {
+		return
+	}
+	var req WishlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wishlist data: " + err.Error()})
+		return
+	}
+	wishlist, err := h.wishlistService.RenameWishlist(c.GetUint("user_id"), id, req.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, wishlist)
+}
+
+func (h *WishlistHandler) DeleteWishlist(c *gin.Context) {
+	id, ok := wishlistIDParam(c)
+	if !ok {
+		return
+	}
+	if err := h.wishlistService.DeleteWishlist(c.GetUint("user_id"), id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Wishlist deleted"})
+}
+
+func (h *WishlistHandler) AddItem(c *gin.Context) {
+	id, ok := wishlistIDParam(c)
+	if !ok {
+		return
+	}
+	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+	if err := h.wishlistService.AddItem(c.GetUint("user_id"), id, uint(productID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Added to wishlist"})
+}
+
+func (h *WishlistHandler) RemoveItem(c *gin.Context) {
+	id, ok := wishlistIDParam(c)
+	if !ok {
+		return
+	}
+	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+	if err := h.wishlistService.RemoveItem(c.GetUint("user_id"), id, uint(productID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Removed from wishlist"})
+}
+
+func (h *WishlistHandler) ShareWishlist(c *gin.Context) {
+	id, ok := wishlistIDParam(c)
+	if !ok {
+		return
+	}
+	wishlist, err := h.wishlistService.Share(c.GetUint("user_id"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"share_token": *wishlist.ShareToken,
+		"url":         "/api/wishlists/shared/" + *wishlist.ShareToken,
+	})
+}
+
+func (h *WishlistHandler) UnshareWishlist(c *gin.Context) {
+	id, ok := wishlistIDParam(c)
+	if !ok {
+		return
+	}
+	if err := h.wishlistService.Unshare(c.GetUint("user_id"), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Wishlist link revoked"})
+}
+
+// GetSharedWishlist — публичный просмотр списка по ссылке, без авторизации.
+func (h *WishlistHandler) GetSharedWishlist(c *gin.Context) {
+	wishlist, err := h.wishlistService.GetShared(c.Param("token"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, wishlist)
+}
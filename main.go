@@ -2,11 +2,15 @@ package main
 
 import (
 	"log"
+	"net/http"
+	"time"
 
+	"1kosmetika-marketplace-backend/carriers"
 	"1kosmetika-marketplace-backend/config"
 	"1kosmetika-marketplace-backend/database"
 	"1kosmetika-marketplace-backend/handlers"
 	"1kosmetika-marketplace-backend/middlewares"
+	"1kosmetika-marketplace-backend/money"
 	"1kosmetika-marketplace-backend/repositories"
 	"1kosmetika-marketplace-backend/routes"
 	"1kosmetika-marketplace-backend/scheduler"
@@ -18,6 +22,7 @@ import (
 func main() {
 
 	cfg := config.Load()
+	money.SetBaseCurrency(cfg.BaseCurrency)
 
 
 	if err := database.ConnectDB(cfg); err != nil {
@@ -35,28 +40,81 @@ func main() {
 	orderRepo := repositories.NewOrderRepository(database.DB)
 	cartRepo := repositories.NewCartRepository(database.DB)
 	favoriteRepo := repositories.NewFavoriteRepository(database.DB)
+	wishlistRepo := repositories.NewWishlistRepository(database.DB)
+	stockSubscriptionRepo := repositories.NewStockSubscriptionRepository(database.DB)
 	reviewRepo := repositories.NewReviewRepository(database.DB)
+	questionRepo := repositories.NewQuestionRepository(database.DB)
+	recommendationRepo := repositories.NewRecommendationRepository(database.DB)
+	recentViewRepo := repositories.NewRecentViewRepository(database.DB)
+	beautyProfileRepo := repositories.NewBeautyProfileRepository(database.DB)
+	ingredientRepo := repositories.NewIngredientRepository(database.DB)
 	notificationRepo := repositories.NewNotificationRepository(database.DB)
 	statsRepo := repositories.NewStatsRepository()
+	priceHistoryRepo := repositories.NewPriceHistoryRepository(database.DB)
+	cartReminderRepo := repositories.NewCartReminderRepository(database.DB)
+	addressRepo := repositories.NewAddressRepository(database.DB)
+	shippingRepo := repositories.NewShippingRepository(database.DB)
+	shipmentRepo := repositories.NewShipmentRepository(database.DB)
+	invoiceRepo := repositories.NewInvoiceRepository(database.DB)
+	taxRepo := repositories.NewTaxRepository(database.DB)
+	exchangeRateRepo := repositories.NewExchangeRateRepository(database.DB)
 
 
 	userService := services.NewUserService(userRepo)
-	productService := services.NewProductService(productRepo)
-	orderService := services.NewOrderService(orderRepo, productRepo, cartRepo, notificationRepo)
-	cartService := services.NewCartService(cartRepo, productRepo)
-	favoriteService := services.NewFavoriteService(favoriteRepo, productRepo)
-	reviewService := services.NewReviewService(reviewRepo, productRepo)
+	productService := services.NewProductService(productRepo, cartRepo, favoriteRepo, notificationRepo, priceHistoryRepo)
+	addressService := services.NewAddressService(addressRepo)
+	shippingService := services.NewShippingService(shippingRepo)
+	currencyService := services.NewCurrencyService(exchangeRateRepo)
+	taxService := services.NewTaxService(taxRepo, productRepo, cfg.PricesIncludeTax)
+	orderService := services.NewOrderService(orderRepo, productRepo, cartRepo, notificationRepo, cartReminderRepo, addressRepo, shippingService, userRepo, taxService)
+	favoriteService := services.NewFavoriteService(favoriteRepo, productRepo, wishlistRepo, notificationRepo, userRepo)
+	wishlistService := services.NewWishlistService(wishlistRepo, favoriteRepo, productRepo)
+	stockAlertService := services.NewStockAlertService(stockSubscriptionRepo, productRepo, notificationRepo)
+	cartService := services.NewCartService(cartRepo, productRepo, favoriteService)
+	reviewService := services.NewReviewService(reviewRepo, productRepo, orderRepo, notificationRepo, cfg.ReviewsRequirePurchase, cfg.ReviewReportThreshold)
+	questionService := services.NewQuestionService(questionRepo, productRepo, orderRepo, notificationRepo)
+	recommendationService := services.NewRecommendationService(recommendationRepo, productRepo)
+	recentViewService := services.NewRecentViewService(recentViewRepo, productRepo)
+	beautyProfileService := services.NewBeautyProfileService(beautyProfileRepo)
+	ingredientService := services.NewIngredientService(ingredientRepo, productRepo)
 	notificationService := services.NewNotificationService(notificationRepo, userRepo)
 	statsService := services.NewStatsService(statsRepo)
-
-	userHandler := handlers.NewUserHandler(userService)
-	productHandler := handlers.NewProductHandler(productService)
-	orderHandler := handlers.NewOrderHandler(orderService)
-	cartHandler := handlers.NewCartHandler(cartService)
+	invoiceService := services.NewInvoiceService(invoiceRepo, orderRepo, services.CompanyInfo{
+		Name:          cfg.CompanyName,
+		Address:       cfg.CompanyAddress,
+		TaxID:         cfg.CompanyTaxID,
+		Phone:         cfg.CompanyPhone,
+		Email:         cfg.CompanyEmail,
+		InvoicePrefix: cfg.InvoicePrefix,
+	})
+	carrierRegistry := carriers.NewRegistry(carrierList(cfg)...)
+	shipmentService := services.NewShipmentService(shipmentRepo, orderRepo, notificationRepo, carrierRegistry)
+	abandonedCartService := services.NewAbandonedCartService(
+		cartRepo, cartReminderRepo, userRepo, notificationRepo,
+		time.Duration(cfg.AbandonedCartHours)*time.Hour,
+	)
+
+	userHandler := handlers.NewUserHandler(userService, cartService)
+	productHandler := handlers.NewProductHandler(productService, currencyService, recentViewService, beautyProfileService, ingredientService)
+	orderHandler := handlers.NewOrderHandler(orderService, invoiceService)
+	cartHandler := handlers.NewCartHandler(cartService, currencyService)
 	favoriteHandler := handlers.NewFavoriteHandler(favoriteService)
+	wishlistHandler := handlers.NewWishlistHandler(wishlistService)
+	stockAlertHandler := handlers.NewStockAlertHandler(stockAlertService)
 	reviewHandler := handlers.NewReviewHandler(reviewService)
+	questionHandler := handlers.NewQuestionHandler(questionService)
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationService, currencyService)
+	recentViewHandler := handlers.NewRecentViewHandler(recentViewService, currencyService)
+	beautyProfileHandler := handlers.NewBeautyProfileHandler(beautyProfileService)
+	ingredientHandler := handlers.NewIngredientHandler(ingredientService)
 	notificationHandler := handlers.NewNotificationHandler(notificationService)
-	statsHandler := handlers.NewStatsHandler(statsService)
+	statsHandler := handlers.NewStatsHandler(statsService, cfg.AbandonedCartHours)
+	sitemapHandler := handlers.NewSitemapHandler(productService, cfg.SiteURL)
+	addressHandler := handlers.NewAddressHandler(addressService)
+	shippingHandler := handlers.NewShippingHandler(shippingService)
+	taxHandler := handlers.NewTaxHandler(taxService)
+	currencyHandler := handlers.NewCurrencyHandler(currencyService)
+	shipmentHandler := handlers.NewShipmentHandler(shipmentService, cfg.CarrierWebhookSecret)
 
 
 	r := gin.Default()
@@ -82,12 +140,36 @@ func main() {
 	routes.SetupOrderRoutes(r, orderHandler)
 	routes.SetupCartRoutes(r, cartHandler)
 	routes.SetupFavoriteRoutes(r, favoriteHandler)
+	routes.SetupWishlistRoutes(r, wishlistHandler)
+	routes.SetupStockAlertRoutes(r, stockAlertHandler)
 	routes.SetupReviewRoutes(r, reviewHandler)
+	routes.SetupQuestionRoutes(r, questionHandler)
+	routes.SetupRecommendationRoutes(r, recommendationHandler)
+	routes.SetupRecentViewRoutes(r, recentViewHandler)
+	routes.SetupBeautyProfileRoutes(r, beautyProfileHandler)
+	routes.SetupIngredientRoutes(r, ingredientHandler)
 	routes.SetupNotificationRoutes(r, notificationHandler)
 	routes.SetupAdminRoutes(r, statsHandler)
+	routes.SetupSitemapRoutes(r, sitemapHandler)
+	routes.SetupAddressRoutes(r, addressHandler)
+	routes.SetupShippingRoutes(r, shippingHandler)
+	routes.SetupShipmentRoutes(r, shipmentHandler)
+	routes.SetupTaxRoutes(r, taxHandler)
+	routes.SetupCurrencyRoutes(r, currencyHandler)
+	if cfg.MockCarrier {
+		r.Any("/mock-carrier/*path", gin.WrapH(http.StripPrefix("/mock-carrier", carriers.MockHandler())))
+	}
 
 
-	scheduler.StartCronJobs()
+	scheduler.StartCronJobs(scheduler.Dependencies{
+		ProductService:        productService,
+		AbandonedCartService:  abandonedCartService,
+		ShipmentService:       shipmentService,
+		FavoriteService:       favoriteService,
+		StockAlertService:     stockAlertService,
+		ReviewService:         reviewService,
+		RecommendationService: recommendationService,
+	})
 
 
 	log.Printf("🚀 Server running on http://localhost:%s", cfg.ServerPort)
@@ -96,3 +178,15 @@ func main() {
 		log.Fatal("❌ Server failed to start:", err)
 	}
 }
+
+// Ручная доставка доступна всегда; внешние перевозчики — по настройкам.
+func carrierList(cfg *config.Config) []carriers.Carrier {
+	list := []carriers.Carrier{carriers.NewManualCarrier()}
+	if cfg.CarrierAPIURL != "" {
+		list = append(list, carriers.NewHTTPCarrier("courier", "Курьерская служба", cfg.CarrierAPIURL, cfg.CarrierAPIKey))
+	}
+	if cfg.MockCarrier {
+		list = append(list, carriers.NewHTTPCarrier(carriers.MockCode, "Тестовый перевозчик", "http://localhost:"+cfg.ServerPort+"/mock-carrier", ""))
+	}
+	return list
+}
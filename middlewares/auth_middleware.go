@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 func JWTAuth() gin.HandlerFunc {
@@ -23,12 +24,44 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
-		c.Set("user_id", uint(claims["user_id"].(float64)))
-		c.Set("role", claims["role"])
+		setClaims(c, claims)
 		c.Next()
 	}
 }
 
+// OptionalJWTAuth пропускает запрос без токена, а с токеном ведёт себя как
+// JWTAuth: по наличию "user_id" обработчик отличает пользователя от гостя.
+func OptionalJWTAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		JWTAuth()(c)
+	}
+}
+
+// SoftJWTAuth — для публичных страниц: просроченный или битый токен не
+// мешает открыть каталог, запрос просто идёт как гостевой.
+func SoftJWTAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if tokenString, err := utils.ExtractTokenFromHeader(c); err == nil {
+			if claims, err := utils.ParseToken(tokenString); err == nil && claims != nil {
+				setClaims(c, claims)
+			}
+		}
+		c.Next()
+	}
+}
+
+func setClaims(c *gin.Context, claims jwt.MapClaims) {
+	c.Set("user_id", uint(claims["user_id"].(float64)))
+	c.Set("role", claims["role"])
+	if email, ok := claims["email"].(string); ok {
+		c.Set("user_email", email)
+	}
+}
+
 func AdminOnly() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
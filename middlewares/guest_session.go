@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"1kosmetika-marketplace-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const guestCookieMaxAge = 30 * 24 * 60 * 60
+
+// GuestSession гарантирует, что у анонимного покупателя есть подписанный токен корзины.
+// Токен кладётся в context под ключом "guest_token".
+func GuestSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := utils.GuestTokenFromRequest(c)
+		if !ok {
+			var signed string
+			id, signed = utils.GenerateGuestToken()
+			c.SetSameSite(http.SameSiteLaxMode)
+			c.SetCookie(utils.GuestCookieName, signed, guestCookieMaxAge, "/", "", false, true)
+			c.Header(utils.GuestTokenHeader, signed)
+		}
+
+		c.Set("guest_token", id)
+		c.Next()
+	}
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Велаяты Туркменистана и Ашхабад как отдельная административная единица.
+var Regions = []string{"ashgabat", "arkadag", "ahal", "balkan", "dashoguz", "lebap", "mary"}
+
+type Address struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"index;not null" json:"user_id"`
+	FullName    string    `gorm:"size:255;not null" json:"full_name" binding:"required"`
+	Phone       string    `gorm:"size:50;not null" json:"phone" binding:"required"`
+	Region      string    `gorm:"size:50;not null" json:"region" binding:"required"`
+	City        string    `gorm:"size:100;not null" json:"city" binding:"required"`
+	AddressLine string    `gorm:"size:500;not null" json:"address_line" binding:"required"`
+	PostalCode  string    `gorm:"size:20" json:"postal_code"`
+	IsDefault   bool      `gorm:"default:false" json:"is_default"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
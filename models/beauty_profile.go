@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Допустимые значения бьюти-профиля и атрибутов товара.
+var (
+	SkinTypes      = []string{"dry", "oily", "combination", "normal", "sensitive"}
+	SkinTones      = []string{"fair", "light", "medium", "tan", "deep"}
+	HairTypes      = []string{"straight", "wavy", "curly", "coily"}
+	BeautyConcerns = []string{
+		"acne", "aging", "pigmentation", "dryness", "redness", "pores",
+		"dark_circles", "dandruff", "hair_loss", "frizz",
+	}
+)
+
+// BeautyProfile — особенности кожи и волос пользователя для подбора товаров.
+// Allergies — ингредиенты в нижнем регистре; товары с ними в составе
+// помечаются предупреждением.
+type BeautyProfile struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"uniqueIndex;not null" json:"user_id"`
+	SkinType  string    `gorm:"size:20" json:"skin_type"`
+	SkinTone  string    `gorm:"size:20" json:"skin_tone"`
+	HairType  string    `gorm:"size:20" json:"hair_type"`
+	Concerns  []string  `gorm:"type:jsonb;serializer:json;not null;default:'[]'" json:"concerns"`
+	Allergies []string  `gorm:"type:jsonb;serializer:json;not null;default:'[]'" json:"allergies"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
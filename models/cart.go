@@ -2,22 +2,26 @@ package models
 
 import (
 	"time"
+
+	"1kosmetika-marketplace-backend/money"
 )
 
 type Cart struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	UserID    uint      `gorm:"uniqueIndex" json:"user_id"`
-	User      User      `json:"user"`
-	Items     []CartItem `json:"items" gorm:"foreignKey:CartID"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     *uint      `gorm:"uniqueIndex" json:"user_id"` // nil — гостевая корзина
+	User       User       `json:"user"`
+	GuestToken string     `gorm:"size:64;index" json:"-"`
+	Items      []CartItem `json:"items" gorm:"foreignKey:CartID"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
 }
 
 type CartItem struct {
-	ID        uint    `gorm:"primaryKey" json:"id"`
-	CartID    uint    `json:"cart_id"`
-	ProductID uint    `json:"product_id"`
-	Product   Product `json:"product" gorm:"foreignKey:ProductID"`
-	Quantity  int     `json:"quantity" binding:"min=1"`
-	Price     float64 `json:"price"`
-}
\ No newline at end of file
+	ID        uint         `gorm:"primaryKey" json:"id"`
+	CartID    uint         `json:"cart_id"`
+	ProductID uint         `json:"product_id"`
+	Product   Product      `json:"product" gorm:"foreignKey:ProductID"`
+	Quantity  int          `json:"quantity" binding:"min=1"`
+	UnitPrice money.Amount `gorm:"default:0" json:"unit_price"`
+	Price     money.Amount `json:"price"` // сумма строки: unit_price * quantity
+}
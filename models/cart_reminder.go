@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"1kosmetika-marketplace-backend/money"
+)
+
+// CartReminder фиксирует отправленное напоминание о брошенной корзине.
+// CartUpdatedAt — версия корзины, для которой оно отправлено: повторно
+// напоминание уходит только если корзину меняли после этого.
+type CartReminder struct {
+	ID               uint         `gorm:"primaryKey" json:"id"`
+	CartID           uint         `gorm:"index;not null" json:"cart_id"`
+	UserID           uint         `gorm:"index;not null" json:"user_id"`
+	CartUpdatedAt    time.Time    `gorm:"not null" json:"cart_updated_at"`
+	CartValue        money.Amount `gorm:"not null;default:0" json:"cart_value"`
+	SentAt           time.Time    `gorm:"not null" json:"sent_at"`
+	RecoveredOrderID *uint        `json:"recovered_order_id"`
+	RecoveredAt      *time.Time   `json:"recovered_at"`
+}
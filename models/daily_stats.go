@@ -1,14 +1,19 @@
 package models
 
-import "time"
+import (
+	"time"
 
+	"1kosmetika-marketplace-backend/money"
+)
 
 type DailyStats struct {
-	ID            uint      `gorm:"primaryKey" json:"id"`
-	Date          string    `json:"date" gorm:"uniqueIndex"`
-	TotalUsers    int64     `json:"total_users"`
-	TotalOrders   int64     `json:"total_orders"`
-	TotalProducts int64     `json:"total_products"`
-	TotalRevenue  float64   `json:"total_revenue"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID            uint         `gorm:"primaryKey" json:"id"`
+	Date          string       `json:"date" gorm:"uniqueIndex"`
+	TotalUsers    int64        `json:"total_users"`
+	TotalOrders   int64        `json:"total_orders"`
+	TotalProducts int64        `json:"total_products"`
+	TotalRevenue  money.Amount `json:"total_revenue"`
+	TotalShipping money.Amount `json:"total_shipping"`
+	TotalTax      money.Amount `json:"total_tax"`
+	CreatedAt     time.Time    `json:"created_at"`
 }
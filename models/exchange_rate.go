@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// ExchangeRate — курс валюты отображения: сколько единиц Currency стоит
+// одна единица базовой валюты магазина.
+type ExchangeRate struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Currency  string    `gorm:"size:3;uniqueIndex;not null" json:"currency"`
+	Rate      float64   `gorm:"not null" json:"rate"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -2,12 +2,23 @@ package models
 
 import (
 	"time"
+
+	"1kosmetika-marketplace-backend/money"
 )
 
+// Favorite — товар в одном из списков желаний пользователя.
+// AlertPrice и AlertInStock — состояние товара на момент последнего
+// уведомления: с ним сравнивается текущая цена и наличие.
 type Favorite struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	UserID    uint      `json:"user_id"`
-	ProductID uint      `json:"product_id"`
-	Product   Product   `json:"product" gorm:"foreignKey:ProductID"`
-	CreatedAt time.Time `json:"created_at"`
-}
\ No newline at end of file
+	ID             uint         `gorm:"primaryKey" json:"id"`
+	UserID         uint         `gorm:"index" json:"user_id"`
+	WishlistID     *uint        `gorm:"uniqueIndex:idx_favorites_wishlist_product" json:"wishlist_id"`
+	ProductID      uint         `gorm:"uniqueIndex:idx_favorites_wishlist_product" json:"product_id"`
+	Product        Product      `json:"product" gorm:"foreignKey:ProductID"`
+	PriceDropAlert bool         `gorm:"not null;default:false" json:"price_drop_alert"`
+	StockAlert     bool         `gorm:"not null;default:false" json:"stock_alert"`
+	AlertEmail     bool         `gorm:"not null;default:false" json:"alert_email"`
+	AlertPrice     money.Amount `gorm:"not null;default:0" json:"-"`
+	AlertInStock   bool         `gorm:"not null;default:false" json:"-"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
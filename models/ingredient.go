@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Признаки ингредиентов для фильтра «без …» (free_from).
+const (
+	IngredientFlagAllergen  = "allergen"
+	IngredientFlagFragrance = "fragrance"
+	IngredientFlagParaben   = "paraben"
+	IngredientFlagSulfate   = "sulfate"
+	IngredientFlagSilicone  = "silicone"
+)
+
+var IngredientFlags = []string{
+	IngredientFlagAllergen, IngredientFlagFragrance, IngredientFlagParaben,
+	IngredientFlagSulfate, IngredientFlagSilicone,
+}
+
+// Ingredient — ингредиент по номенклатуре INCI. INCIName уникален без учёта
+// регистра; CommonNames — бытовые названия для поиска («витамин B3»).
+type Ingredient struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	INCIName    string    `gorm:"column:inci_name;size:255;not null" json:"inci_name"`
+	CommonNames []string  `gorm:"type:jsonb;serializer:json;not null;default:'[]'" json:"common_names"`
+	Description string    `gorm:"type:text" json:"description"`
+	IsAllergen  bool      `gorm:"not null;default:false" json:"is_allergen"`
+	IsFragrance bool      `gorm:"not null;default:false" json:"is_fragrance"`
+	IsParaben   bool      `gorm:"not null;default:false" json:"is_paraben"`
+	IsSulfate   bool      `gorm:"not null;default:false" json:"is_sulfate"`
+	IsSilicone  bool      `gorm:"not null;default:false" json:"is_silicone"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ProductIngredient связывает товар с ингредиентами его состава; Position —
+// порядок в составе (по убыванию концентрации, как на упаковке).
+type ProductIngredient struct {
+	ProductID    uint `gorm:"primaryKey" json:"product_id"`
+	IngredientID uint `gorm:"primaryKey;index" json:"ingredient_id"`
+	Position     int  `gorm:"not null" json:"position"`
+}
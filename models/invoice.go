@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"1kosmetika-marketplace-backend/money"
+)
+
+// Invoice хранит готовый PDF: повторная печать отдаёт те же байты,
+// даже если заказ или реквизиты компании потом изменились.
+type Invoice struct {
+	ID        uint         `gorm:"primaryKey" json:"id"`
+	OrderID   uint         `gorm:"uniqueIndex;not null" json:"order_id"`
+	Number    string       `gorm:"size:50;uniqueIndex;not null" json:"number"`
+	IssuedAt  time.Time    `gorm:"not null" json:"issued_at"`
+	Total     money.Amount `gorm:"not null;default:0" json:"total"`
+	PDF       []byte       `gorm:"type:bytea;not null" json:"-"`
+	CreatedAt time.Time    `json:"created_at"`
+}
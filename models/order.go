@@ -1,23 +1,78 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"1kosmetika-marketplace-backend/money"
+)
+
+const (
+	OrderStatusPending    = "pending"
+	OrderStatusProcessing = "processing"
+	OrderStatusShipped    = "shipped"
+	OrderStatusCompleted  = "completed"
+	OrderStatusCancelled  = "cancelled"
+	OrderStatusRefunded   = "refunded"
+)
+
+// Отдельной платёжной записи нет, состояние оплаты хранится в самом заказе.
+const (
+	PaymentStatusUnpaid   = "unpaid"
+	PaymentStatusPaid     = "paid"
+	PaymentStatusVoided   = "voided"
+	PaymentStatusRefunded = "refunded"
+)
+
+// Покупатель может отменить заказ только до передачи в доставку.
+var CancellableOrderStatuses = []string{OrderStatusPending, OrderStatusProcessing}
 
 type Order struct {
-	ID            uint      `gorm:"primaryKey" json:"id"`
-	UserID        uint      `json:"user_id"`
-	User          User      `gorm:"foreignKey:UserID" json:"user"`
-	Products      []Product `gorm:"many2many:order_products;" json:"products"`
-	Total         float64   `json:"total"`
-	Status        string    `gorm:"default:pending" json:"status"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
-	TotalAmount   float64   `json:"total_amount"` 
-	PaymentMethod string    `json:"payment_method"`
+	ID            uint         `gorm:"primaryKey" json:"id"`
+	UserID        *uint        `json:"user_id"` // nil — гостевой заказ
+	User          User         `gorm:"foreignKey:UserID" json:"user"`
+	Products      []Product    `gorm:"many2many:order_products;" json:"products"`
+	Total         money.Amount `json:"total"`
+	Status        string       `gorm:"default:pending" json:"status"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	TotalAmount   money.Amount `json:"total_amount"`
+	PaymentMethod string       `json:"payment_method"`
+	PaymentStatus string       `gorm:"size:20;default:unpaid" json:"payment_status"`
+
+	CancelReason string     `gorm:"size:500" json:"cancel_reason,omitempty"`
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty"`
+	// Остаток списан при оформлении; заказы, оформленные до резервирования,
+	// остаются false, и отмена не возвращает за них товар на склад.
+	StockReserved bool `gorm:"not null;default:false" json:"-"`
+
+	GuestEmail string `gorm:"size:255" json:"guest_email,omitempty"`
+	GuestName  string `gorm:"size:255" json:"guest_name,omitempty"`
+	GuestPhone string `gorm:"size:50" json:"guest_phone,omitempty"`
+
+	ShippingFullName   string       `gorm:"size:255" json:"shipping_full_name"`
+	ShippingPhone      string       `gorm:"size:50" json:"shipping_phone"`
+	ShippingCity       string       `gorm:"size:100" json:"shipping_city"`
+	ShippingAddress    string       `gorm:"size:500" json:"shipping_address"`
+	ShippingRegion     string       `gorm:"size:50" json:"shipping_region"`
+	ShippingPostalCode string       `gorm:"size:20" json:"shipping_postal_code"`
+	ShippingMethodID   *uint        `json:"shipping_method_id"`
+	ShippingMethodName string       `gorm:"size:255" json:"shipping_method_name"`
+	ShippingCost       money.Amount `gorm:"default:0" json:"shipping_cost"`
+	// Subtotal — сумма товаров по ценам витрины. Если цены без налога,
+	// Total = Subtotal + TaxTotal + ShippingCost, иначе налог уже внутри Subtotal.
+	Subtotal money.Amount `gorm:"default:0" json:"subtotal"`
+	TaxTotal money.Amount `gorm:"default:0" json:"tax_total"`
+	// Валюта расчёта — базовая валюта магазина на момент оформления.
+	Currency         string `gorm:"size:3" json:"currency"`
+	PricesIncludeTax bool   `gorm:"not null;default:false" json:"prices_include_tax"`
 }
 
 type OrderProduct struct {
-    OrderID   uint    `gorm:"primaryKey"`
-    ProductID uint    `gorm:"primaryKey"`
-    Quantity  int     `gorm:"not null;default:1"`
-    Price     float64 `gorm:"not null;default:0"`
+	OrderID   uint         `gorm:"primaryKey"`
+	ProductID uint         `gorm:"primaryKey"`
+	Quantity  int          `gorm:"not null;default:1"`
+	Price     money.Amount `gorm:"not null;default:0"`
+	TaxRate   float64      `gorm:"not null;default:0"` // в процентах
+	TaxAmount money.Amount `gorm:"not null;default:0"`
+	NetAmount money.Amount `gorm:"not null;default:0"` // сумма строки без налога
 }
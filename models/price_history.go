@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"1kosmetika-marketplace-backend/money"
+)
+
+const (
+	PriceChangeManual    = "manual"
+	PriceChangeSaleStart = "sale_start"
+	PriceChangeSaleEnd   = "sale_end"
+)
+
+type PriceHistory struct {
+	ID        uint         `gorm:"primaryKey" json:"id"`
+	ProductID uint         `gorm:"index;not null" json:"product_id"`
+	OldPrice  money.Amount `gorm:"not null" json:"old_price"`
+	NewPrice  money.Amount `gorm:"not null" json:"new_price"`
+	Reason    string       `gorm:"size:30;not null" json:"reason"` // manual, sale_start, sale_end
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+func (PriceHistory) TableName() string {
+	return "price_history"
+}
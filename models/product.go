@@ -1,18 +1,126 @@
 package models
 
 import (
+	"strings"
 	"time"
+
+	"1kosmetika-marketplace-backend/money"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ProductStatusDraft    = "draft"
+	ProductStatusActive   = "active"
+	ProductStatusArchived = "archived"
 )
 
 type Product struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Name        string    `gorm:"not null" json:"name" binding:"required"`
-	Description string    `json:"description"`
-	Price       float64   `gorm:"not null" json:"price" binding:"required,gt=0"`
-	ImageURL    string    `json:"image_url"`
-	Category    string    `json:"category" binding:"required"`
-	Brand       string    `json:"brand" binding:"required"`
-	Stock       int       `gorm:"default:0" json:"stock"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-}
\ No newline at end of file
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	Name            string         `gorm:"not null" json:"name" binding:"required"`
+	Description     string         `json:"description"`
+	Price           money.Amount   `gorm:"not null" json:"price" binding:"required,gt=0"`
+	ImageURL        string         `json:"image_url"`
+	Category        string         `json:"category" binding:"required"`
+	Brand           string         `json:"brand" binding:"required"`
+	Stock           int            `gorm:"default:0" json:"stock"`
+	CompareAtPrice  money.Amount   `gorm:"default:0" json:"compare_at_price"` // зачёркнутая цена
+	SalePrice       money.Amount   `gorm:"default:0" json:"sale_price"`
+	SaleStartsAt    *time.Time     `json:"sale_starts_at"`
+	SaleEndsAt      *time.Time     `json:"sale_ends_at"`
+	SaleActive      bool           `gorm:"default:false" json:"sale_active"`
+	SKU             string         `gorm:"size:64;uniqueIndex" json:"sku"`
+	Slug            string         `gorm:"size:160;uniqueIndex" json:"slug"`
+	MetaTitle       string         `gorm:"size:255" json:"meta_title"`
+	MetaDescription string         `gorm:"size:500" json:"meta_description"`
+	Status          string         `gorm:"size:20;default:active;index" json:"status"` // draft, active, archived
+	PublishAt       *time.Time     `json:"publish_at"`
+	UnpublishAt     *time.Time     `json:"unpublish_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Сводка по опубликованным отзывам; пересчитывается в одной транзакции с отзывом.
+	RatingAvg   float64 `gorm:"not null;default:0;index" json:"rating_avg"`
+	RatingCount int     `gorm:"not null;default:0" json:"rating_count"`
+
+	// Просмотры карточки; счётчик ведёт RecentViewService.
+	ViewCount int64 `gorm:"not null;default:0;index" json:"view_count"`
+
+	// Для кого подходит товар; пустой список — подходит всем.
+	SkinTypes []string `gorm:"type:jsonb;serializer:json;not null;default:'[]'" json:"skin_types"`
+	HairTypes []string `gorm:"type:jsonb;serializer:json;not null;default:'[]'" json:"hair_types"`
+	Concerns  []string `gorm:"type:jsonb;serializer:json;not null;default:'[]'" json:"concerns"`
+	// Состав (INCI) как на упаковке, через запятую. Пишется только вместе
+	// с product_ingredients через IngredientService.SetProductINCI.
+	Ingredients string `gorm:"type:text;not null;default:''" json:"ingredients"`
+
+	Display *PriceDisplay `gorm:"-" json:"display,omitempty"`
+
+	// Аллергены из профиля покупателя, найденные в составе.
+	AllergenWarnings []string `gorm:"-" json:"allergen_warnings,omitempty"`
+}
+
+// PriceDisplay — цены товара, пересчитанные в валюту отображения (?currency=).
+// Оплата и заказы остаются в базовой валюте.
+type PriceDisplay struct {
+	Currency       string       `json:"currency"`
+	Price          money.Amount `json:"price"`
+	CompareAtPrice money.Amount `json:"compare_at_price"`
+	SalePrice      money.Amount `json:"sale_price"`
+}
+
+// ApplyCurrency заполняет Display; nil-конвертер означает базовую валюту.
+func (p *Product) ApplyCurrency(conv *money.Converter) {
+	if conv == nil {
+		return
+	}
+	p.Display = &PriceDisplay{
+		Currency:       conv.Currency,
+		Price:          conv.Convert(p.Price),
+		CompareAtPrice: conv.Convert(p.CompareAtPrice),
+		SalePrice:      conv.Convert(p.SalePrice),
+	}
+}
+
+// NormalizeINCIEntry приводит компонент состава к виду для сравнения:
+// нижний регистр, одиночные пробелы, без точки в конце.
+func NormalizeINCIEntry(name string) string {
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+	return strings.TrimSpace(strings.TrimRight(name, "."))
+}
+
+// FlagAllergens заполняет AllergenWarnings аллергенами, которые совпадают
+// с целым компонентом состава (без учёта регистра): «paraben» не находит
+// «methylparaben», а «oil» — любое масло. Кроме текста состава сверяются
+// INCI и бытовые названия связанных ингредиентов.
+func (p *Product) FlagAllergens(allergies []string, ingredients []Ingredient) {
+	p.AllergenWarnings = nil
+	entries := make(map[string]bool)
+	add := func(name string) {
+		entries[NormalizeINCIEntry(name)] = true
+	}
+	for _, entry := range strings.Split(p.Ingredients, ",") {
+		add(entry)
+	}
+	for _, ingredient := range ingredients {
+		add(ingredient.INCIName)
+		for _, name := range ingredient.CommonNames {
+			add(name)
+		}
+	}
+	for _, allergen := range allergies {
+		if allergen != "" && entries[allergen] {
+			p.AllergenWarnings = append(p.AllergenWarnings, allergen)
+		}
+	}
+}
+
+// ProductSlugRedirect хранит старые slug'и переименованных товаров,
+// чтобы старые ссылки продолжали работать.
+type ProductSlugRedirect struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Slug      string    `gorm:"size:160;uniqueIndex;not null" json:"slug"`
+	ProductID uint      `gorm:"index;not null" json:"product_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
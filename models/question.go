@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+const (
+	QuestionStatusPending  = "pending"
+	QuestionStatusApproved = "approved"
+	QuestionStatusRejected = "rejected"
+)
+
+// ProductQuestion — вопрос покупателя о товаре. На витрине видны только
+// одобренные модератором вопросы.
+type ProductQuestion struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	ProductID   uint            `gorm:"index;not null" json:"product_id"`
+	UserID      uint            `gorm:"index;not null" json:"user_id"`
+	User        User            `json:"user" gorm:"foreignKey:UserID"`
+	Body        string          `gorm:"type:text;not null" json:"body"`
+	Status      string          `gorm:"size:20;not null;default:pending;index" json:"status"` // pending, approved, rejected
+	AnswerCount int             `gorm:"not null;default:0" json:"answer_count"`
+	Answers     []ProductAnswer `json:"answers" gorm:"foreignKey:QuestionID"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// ProductAnswer — ответ администратора (IsOfficial) или покупателя товара.
+type ProductAnswer struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	QuestionID uint      `gorm:"index;not null" json:"question_id"`
+	UserID     uint      `gorm:"not null" json:"user_id"`
+	User       User      `json:"user" gorm:"foreignKey:UserID"`
+	Body       string    `gorm:"type:text;not null" json:"body"`
+	IsOfficial bool      `gorm:"not null;default:false" json:"is_official"`
+	Upvotes    int       `gorm:"not null;default:0" json:"upvotes"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type AnswerVote struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	AnswerID  uint      `gorm:"uniqueIndex:idx_answer_votes_answer_user;not null" json:"answer_id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_answer_votes_answer_user;not null" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// RecentView — товар, который пользователь недавно открывал. Одна запись
+// на пару пользователь/товар, повторный просмотр обновляет ViewedAt.
+type RecentView struct {
+	UserID    uint      `gorm:"primaryKey" json:"user_id"`
+	ProductID uint      `gorm:"primaryKey" json:"product_id"`
+	ViewedAt  time.Time `gorm:"not null;index" json:"viewed_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const (
+	RelationSourceCoPurchase = "co_purchase"
+	RelationSourceSimilar    = "similar"
+)
+
+// ProductRelation — предрасчитанная рекомендация «с этим товаром покупают».
+// Score для совместных покупок — число заказов (>= 1), для похожих товаров
+// той же категории/бренда — меньше 1, поэтому они идут после покупок.
+type ProductRelation struct {
+	ProductID        uint      `gorm:"primaryKey" json:"product_id"`
+	RelatedProductID uint      `gorm:"primaryKey" json:"related_product_id"`
+	Score            float64   `gorm:"not null;index" json:"score"`
+	Source           string    `gorm:"size:20;not null" json:"source"` // co_purchase, similar
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// UserRecommendation — предрасчитанная персональная рекомендация по заказам
+// и избранному пользователя.
+type UserRecommendation struct {
+	UserID    uint      `gorm:"primaryKey" json:"user_id"`
+	ProductID uint      `gorm:"primaryKey" json:"product_id"`
+	Score     float64   `gorm:"not null" json:"score"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
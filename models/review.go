@@ -4,14 +4,71 @@ import (
 	"time"
 )
 
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+)
+
+// Отзыв попадает на витрину только после модерации. VerifiedPurchase
+// ставится, если у автора есть выполненный заказ с этим товаром.
 type Review struct {
+	ID               uint          `gorm:"primaryKey" json:"id"`
+	UserID           uint          `json:"user_id"`
+	User             User          `json:"user" gorm:"foreignKey:UserID"`
+	ProductID        uint          `json:"product_id"`
+	Product          Product       `json:"product" gorm:"foreignKey:ProductID"`
+	Rating           int           `json:"rating" binding:"required,min=1,max=5"`
+	Comment          string        `json:"comment" binding:"max=500"`
+	VerifiedPurchase bool          `gorm:"not null;default:false" json:"verified_purchase"`
+	Status           string        `gorm:"size:20;not null;default:pending;index" json:"status"` // pending, approved, rejected
+	ModerationNote   string        `gorm:"size:500" json:"moderation_note,omitempty"`
+	ModeratedAt      *time.Time    `json:"moderated_at,omitempty"`
+	Photos           []ReviewPhoto `json:"photos" gorm:"foreignKey:ReviewID"`
+	HelpfulCount     int           `gorm:"not null;default:0" json:"helpful_count"`
+	UnhelpfulCount   int           `gorm:"not null;default:0" json:"unhelpful_count"`
+	ReportCount      int           `gorm:"not null;default:0" json:"report_count"`
+	Replies          []ReviewReply `json:"replies" gorm:"foreignKey:ReviewID"`
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+}
+
+type ReviewPhoto struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ReviewID  uint      `gorm:"index;not null" json:"review_id"`
+	URL       string    `gorm:"size:500;not null" json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReviewReply — официальный ответ магазина на отзыв; пишут только администраторы.
+type ReviewReply struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ReviewID  uint      `gorm:"index;not null" json:"review_id"`
+	AuthorID  uint      `gorm:"not null" json:"author_id"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+var ReviewReportReasons = []string{"spam", "offensive", "fake", "off_topic", "other"}
+
+// ReviewReport — жалоба на отзыв; одна жалоба пользователя на отзыв.
+type ReviewReport struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ReviewID  uint      `gorm:"uniqueIndex:idx_review_reports_review_user;not null" json:"review_id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_review_reports_review_user;not null" json:"user_id"`
+	Reason    string    `gorm:"size:30;not null" json:"reason"`
+	Comment   string    `gorm:"size:500" json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReviewVote — оценка полезности отзыва; один голос пользователя на отзыв.
+// Счётчики в Review пересчитываются при каждом голосе.
+type ReviewVote struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
-	UserID    uint      `json:"user_id"`
-	User      User      `json:"user" gorm:"foreignKey:UserID"`
-	ProductID uint      `json:"product_id"`
-	Product   Product   `json:"product" gorm:"foreignKey:ProductID"`
-	Rating    int       `json:"rating" binding:"required,min=1,max=5"`
-	Comment   string    `json:"comment" binding:"max=500"`
+	ReviewID  uint      `gorm:"uniqueIndex:idx_review_votes_review_user;not null" json:"review_id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_review_votes_review_user;not null" json:"user_id"`
+	Helpful   bool      `gorm:"not null" json:"helpful"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+const (
+	ShipmentStatusCreated        = "created"
+	ShipmentStatusInTransit      = "in_transit"
+	ShipmentStatusOutForDelivery = "out_for_delivery"
+	ShipmentStatusDelivered      = "delivered"
+	ShipmentStatusFailed         = "failed"
+	ShipmentStatusReturned       = "returned"
+)
+
+type Shipment struct {
+	ID             uint            `gorm:"primaryKey" json:"id"`
+	OrderID        uint            `gorm:"index;not null" json:"order_id"`
+	Carrier        string          `gorm:"size:50;not null;uniqueIndex:idx_shipment_tracking" json:"carrier"`
+	TrackingNumber string          `gorm:"size:100;not null;uniqueIndex:idx_shipment_tracking" json:"tracking_number"`
+	Status         string          `gorm:"size:30;default:created;index" json:"status"`
+	LastCheckedAt  *time.Time      `json:"last_checked_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	Events         []ShipmentEvent `gorm:"foreignKey:ShipmentID" json:"events,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// Одно и то же событие от перевозчика может прийти и вебхуком, и при опросе,
+// поэтому (shipment, status, occurred_at) уникальны.
+type ShipmentEvent struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ShipmentID  uint      `gorm:"not null;uniqueIndex:idx_shipment_event" json:"shipment_id"`
+	Status      string    `gorm:"size:30;not null;uniqueIndex:idx_shipment_event" json:"status"`
+	Description string    `gorm:"size:500" json:"description"`
+	Location    string    `gorm:"size:255" json:"location"`
+	OccurredAt  time.Time `gorm:"not null;uniqueIndex:idx_shipment_event" json:"occurred_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Финальные статусы больше не опрашиваются у перевозчика.
+func (s *Shipment) IsFinal() bool {
+	return s.Status == ShipmentStatusDelivered || s.Status == ShipmentStatusReturned
+}
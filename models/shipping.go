@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"1kosmetika-marketplace-backend/money"
+)
+
+type ShippingMethod struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Code        string `gorm:"size:50;uniqueIndex;not null" json:"code" binding:"required"`
+	Name        string `gorm:"size:255;not null" json:"name" binding:"required"`
+	Description string `gorm:"type:text" json:"description"`
+	// Бесплатная доставка от этой суммы заказа; 0 — порога нет.
+	FreeShippingThreshold money.Amount   `gorm:"default:0" json:"free_shipping_threshold"`
+	EstimatedDays         string         `gorm:"size:50" json:"estimated_days"`
+	IsActive              bool           `gorm:"not null" json:"is_active"`
+	Rates                 []ShippingRate `gorm:"foreignKey:ShippingMethodID" json:"rates,omitempty"`
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
+}
+
+// ShippingRate — тариф по зоне. Пустой City означает весь велаят,
+// пустой Region — тариф по умолчанию для всей страны.
+type ShippingRate struct {
+	ID               uint         `gorm:"primaryKey" json:"id"`
+	ShippingMethodID uint         `gorm:"index;not null" json:"shipping_method_id"`
+	Region           string       `gorm:"size:50" json:"region"`
+	City             string       `gorm:"size:100" json:"city"`
+	Price            money.Amount `gorm:"not null;default:0" json:"price" binding:"gte=0"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// StockSubscription — просьба сообщить о поступлении товара. Гость
+// подписывается по email, у пользователя дополнительно заполнен UserID.
+// NotifiedAt nil — уведомление ещё не отправлено.
+type StockSubscription struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	ProductID  uint       `gorm:"uniqueIndex:idx_stock_subscriptions_product_email;not null" json:"product_id"`
+	UserID     *uint      `gorm:"index" json:"user_id"`
+	Email      string     `gorm:"size:255;uniqueIndex:idx_stock_subscriptions_product_email;not null" json:"email"`
+	NotifiedAt *time.Time `gorm:"index" json:"notified_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// TaxRate — ставка налога в процентах. Применяется правило с самой узкой
+// областью: ставка товара, затем категории, затем ставка по умолчанию
+// (без товара и категории).
+type TaxRate struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:100;not null" json:"name" binding:"required"`
+	Rate      float64   `gorm:"not null" json:"rate" binding:"gte=0,lte=100"`
+	ProductID *uint     `gorm:"index" json:"product_id"`
+	Category  string    `gorm:"size:255;index" json:"category"`
+	IsActive  bool      `gorm:"not null" json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -5,12 +5,13 @@ import (
 )
 
 type User struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	FullName  string    `json:"full_name" binding:"required"`
-	Email     string    `gorm:"uniqueIndex;not null" json:"email" binding:"required,email"`
-	Password  string    `json:"-" binding:"required,min=6"`
-	Role      string    `gorm:"default:user" json:"role"` // user/admin
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Orders    []Order   `json:"orders,omitempty"`
-}
\ No newline at end of file
+	ID                  uint      `gorm:"primaryKey" json:"id"`
+	FullName            string    `json:"full_name" binding:"required"`
+	Email               string    `gorm:"uniqueIndex;not null" json:"email" binding:"required,email"`
+	Password            string    `json:"-" binding:"required,min=6"`
+	Role                string    `gorm:"default:user" json:"role"` // user/admin
+	CartRemindersOptOut bool      `gorm:"default:false" json:"cart_reminders_opt_out"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+	Orders              []Order   `json:"orders,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// DefaultWishlistName — список, в который попадает «избранное» без выбора списка.
+const DefaultWishlistName = "Избранное"
+
+// Wishlist — именованный список желаний. По ShareToken список доступен
+// всем только для чтения; nil — ссылка не выдана или отозвана.
+type Wishlist struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"index;not null" json:"user_id"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	IsDefault  bool       `gorm:"not null;default:false" json:"is_default"`
+	ShareToken *string    `gorm:"size:64;uniqueIndex" json:"share_token,omitempty"`
+	Items      []Favorite `gorm:"foreignKey:WishlistID" json:"items"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
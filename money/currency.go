@@ -0,0 +1,71 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Money — сумма вместе с кодом валюты, для ответов API.
+type Money struct {
+	Amount   Amount `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func New(amount Amount, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
+
+// NormalizeCurrency проверяет ISO 4217 код из трёх латинских букв.
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", fmt.Errorf("invalid currency code %q", code)
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("invalid currency code %q", code)
+		}
+	}
+	return code, nil
+}
+
+// Converter пересчитывает суммы базовой валюты в валюту отображения.
+// Rate — сколько единиц целевой валюты стоит одна единица базовой.
+type Converter struct {
+	Currency string
+	Rate     float64
+}
+
+func (c *Converter) Convert(a Amount) Amount {
+	return a.MulRate(c.Rate)
+}
+
+// ToBase — обратный пересчёт суммы, введённой в валюте отображения
+// (например, границы фильтра по цене). Без конвертера сумма уже в базовой.
+func (c *Converter) ToBase(a Amount) Amount {
+	if c == nil || c.Rate <= 0 {
+		return a
+	}
+	return a.MulRate(1 / c.Rate)
+}
+
+// Базовая валюта магазина: в ней хранятся цены и рассчитываются заказы.
+// Задаётся один раз при старте из конфигурации.
+var baseCurrency = "TMT"
+
+func SetBaseCurrency(code string) {
+	baseCurrency = code
+}
+
+func BaseCurrency() string {
+	return baseCurrency
+}
+
+// Format — сумма в базовой валюте для текстов писем и уведомлений: "12.34 TMT".
+func Format(a Amount) string {
+	return New(a, baseCurrency).String()
+}
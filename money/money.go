@@ -0,0 +1,189 @@
+// Package money хранит денежные суммы в целых минимальных единицах
+// (тыйынах, центах), чтобы итоги сходились без ошибок округления float64.
+//
+// Правила округления:
+//   - внешний ввод (JSON, query, старые float-колонки) округляется до
+//     минимальной единицы половиной от нуля: 0.125 → 0.13;
+//   - умножение на количество точное, на ставку (налог, курс) округляется
+//     так же, один раз на строку;
+//   - итоги заказа, корзины и статистики — только суммы уже округлённых
+//     строк, поэтому сумма строк всегда равна итогу, а выручка в
+//     статистике — сумме итогов заказов.
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Все поддерживаемые валюты делятся на 100 минимальных единиц.
+const Scale = 100
+
+// Amount — сумма в минимальных единицах базовой валюты.
+// В JSON пишется как десятичное число с двумя знаками: 1234 → 12.34.
+type Amount int64
+
+// FromFloat переводит число в сумму с округлением половины от нуля.
+// Нужен только на границе с внешними данными; внутри расчёты идут в Amount.
+func FromFloat(f float64) Amount {
+	return Amount(math.Round(f * Scale))
+}
+
+// FromMinor создаёт сумму из минимальных единиц.
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// Parse разбирает десятичную строку ("12", "12.5", "-0.35") без потери точности.
+// Знаки после второго округляются половиной от нуля.
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty amount")
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	for _, part := range []string{whole, frac} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return 0, fmt.Errorf("invalid amount %q", s)
+			}
+		}
+	}
+
+	var units int64
+	if whole != "" {
+		n, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || n > math.MaxInt64/Scale-1 {
+			return 0, fmt.Errorf("amount %q is out of range", s)
+		}
+		units = n * Scale
+	}
+
+	roundUp := len(frac) > 2 && frac[2] >= '5'
+	frac = (frac + "00")[:2]
+	cents, _ := strconv.ParseInt(frac, 10, 64)
+	units += cents
+	if roundUp {
+		units++
+	}
+
+	if negative {
+		units = -units
+	}
+	return Amount(units), nil
+}
+
+// Minor возвращает сумму в минимальных единицах.
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Float — только для отображения и внешних API, не для расчётов.
+func (a Amount) Float() float64 {
+	return float64(a) / Scale
+}
+
+func (a Amount) String() string {
+	sign := ""
+	v := int64(a)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/Scale, v%Scale)
+}
+
+func (a Amount) Mul(qty int) Amount {
+	return a * Amount(qty)
+}
+
+// MulRate умножает сумму на дробный коэффициент (курс валюты, процент/100)
+// с округлением половины от нуля.
+func (a Amount) MulRate(rate float64) Amount {
+	return Amount(math.Round(float64(a) * rate))
+}
+
+func (a Amount) IsZero() bool {
+	return a == 0
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON принимает и число, и строку: 12.34 и "12.34".
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" || s == "" {
+		*a = 0
+		return nil
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+func (a Amount) Value() (driver.Value, error) {
+	return int64(a), nil
+}
+
+func (a *Amount) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*a = 0
+	case int64:
+		*a = Amount(v)
+	case int32:
+		*a = Amount(v)
+	case float64:
+		*a = Amount(math.Round(v))
+	case []byte:
+		n, err := strconv.ParseInt(string(v), 10, 64)
+		if err != nil {
+			return fmt.Errorf("money: cannot scan %q", v)
+		}
+		*a = Amount(n)
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("money: cannot scan %q", v)
+		}
+		*a = Amount(n)
+	default:
+		return fmt.Errorf("money: cannot scan %T", value)
+	}
+	return nil
+}
+
+// GormDataType — суммы хранятся в bigint.
+func (Amount) GormDataType() string {
+	return "bigint"
+}
+
+// Sum складывает суммы.
+func Sum(amounts ...Amount) Amount {
+	var total Amount
+	for _, a := range amounts {
+		total += a
+	}
+	return total
+}
@@ -0,0 +1,133 @@
+package money
+
+import "testing"
+
+func mustParse(t *testing.T, s string) Amount {
+	t.Helper()
+	a, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", s, err)
+	}
+	return a
+}
+
+func TestParseAddsExactly(t *testing.T) {
+	if got, want := mustParse(t, "0.1")+mustParse(t, "0.2"), mustParse(t, "0.3"); got != want {
+		t.Fatalf("0.1 + 0.2 = %s, want %s", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+	}{
+		{"12", 1200},
+		{"12.5", 1250},
+		{"12.34", 1234},
+		{".5", 50},
+		{"-0.35", -35},
+		{"+1.01", 101},
+		{"0.125", 13},
+		{"0.124", 12},
+		{"-0.125", -13},
+		{" 7.00 ", 700},
+	}
+	for _, tt := range tests {
+		if got := mustParse(t, tt.in); got != tt.want {
+			t.Errorf("Parse(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", ".", "-", "1,5", "1.2.3", "abc", "1e3"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestFromFloatRoundsHalfAwayFromZero(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want Amount
+	}{
+		{0.125, 13},
+		{-0.125, -13},
+		{0.124, 12},
+		{-0.124, -12},
+		{0.1 + 0.2, 30},
+		{19.99, 1999},
+	}
+	for _, tt := range tests {
+		if got := FromFloat(tt.in); got != tt.want {
+			t.Errorf("FromFloat(%v) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMulRate(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		rate   float64
+		want   Amount
+	}{
+		{1000, 0.15, 150},
+		{1, 0.5, 1},   // 0.5 тыйына → 1
+		{-1, 0.5, -1}, // и от нуля для отрицательных
+		{333, 0.15, 50},
+		{1150, 15.0 / 115, 150},
+		{10000, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.MulRate(tt.rate); got != tt.want {
+			t.Errorf("%d.MulRate(%v) = %d, want %d", tt.amount, tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestSum(t *testing.T) {
+	if got := Sum(); got != 0 {
+		t.Errorf("Sum() = %d, want 0", got)
+	}
+	if got := Sum(10, 20, -5, 1999); got != 2024 {
+		t.Errorf("Sum = %d, want 2024", got)
+	}
+}
+
+func TestStringAndJSON(t *testing.T) {
+	tests := []struct {
+		in   Amount
+		want string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{1234, "12.34"},
+		{-35, "-0.35"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Amount(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+		var back Amount
+		if err := back.UnmarshalJSON([]byte(tt.want)); err != nil || back != tt.in {
+			t.Errorf("UnmarshalJSON(%q) = %d, %v; want %d", tt.want, back, err, tt.in)
+		}
+	}
+}
+
+func TestConverterToBase(t *testing.T) {
+	var none *Converter
+	if got := none.ToBase(1000); got != 1000 {
+		t.Errorf("nil converter ToBase = %d, want 1000", got)
+	}
+
+	usd := &Converter{Currency: "USD", Rate: 0.2857}
+	if got := usd.ToBase(1000); got != 3500 {
+		t.Errorf("ToBase(10.00 USD) = %s, want 35.00", got)
+	}
+	for _, a := range []Amount{100, 999, 12345} {
+		if back := usd.Convert(usd.ToBase(a)); back != a {
+			t.Errorf("Convert(ToBase(%s)) = %s", a, back)
+		}
+	}
+}
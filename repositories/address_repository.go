@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"1kosmetika-marketplace-backend/models"
+
+	"gorm.io/gorm"
+)
+
+type AddressRepository interface {
+	Create(address *models.Address) error
+	Update(address *models.Address) error
+	Delete(userID, addressID uint) error
+	FindByID(addressID uint) (*models.Address, error)
+	FindByUserID(userID uint) ([]models.Address, error)
+	FindDefault(userID uint) (*models.Address, error)
+	SetDefault(userID, addressID uint) error
+}
+
+type addressRepository struct {
+	db *gorm.DB
+}
+
+func NewAddressRepository(db *gorm.DB) AddressRepository {
+	return &addressRepository{db: db}
+}
+
+func (r *addressRepository) Create(address *models.Address) error {
+	return r.db.Create(address).Error
+}
+
+func (r *addressRepository) Update(address *models.Address) error {
+	return r.db.Save(address).Error
+}
+
+func (r *addressRepository) Delete(userID, addressID uint) error {
+	return r.db.Where("id = ? AND user_id = ?", addressID, userID).Delete(&models.Address{}).Error
+}
+
+func (r *addressRepository) FindByID(addressID uint) (*models.Address, error) {
+	var address models.Address
+	err := r.db.First(&address, addressID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (r *addressRepository) FindByUserID(userID uint) ([]models.Address, error) {
+	var addresses []models.Address
+	err := r.db.Where("user_id = ?", userID).Order("is_default DESC, created_at DESC").Find(&addresses).Error
+	return addresses, err
+}
+
+func (r *addressRepository) FindDefault(userID uint) (*models.Address, error) {
+	var address models.Address
+	err := r.db.Where("user_id = ? AND is_default = ?", userID, true).First(&address).Error
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// У пользователя всегда не больше одного адреса по умолчанию.
+func (r *addressRepository) SetDefault(userID, addressID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Address{}).
+			Where("user_id = ? AND is_default = ?", userID, true).
+			Update("is_default", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Address{}).
+			Where("id = ? AND user_id = ?", addressID, userID).
+			Update("is_default", true).Error
+	})
+}
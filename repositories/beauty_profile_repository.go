@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"1kosmetika-marketplace-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BeautyProfileRepository interface {
+	FindByUserID(userID uint) (*models.BeautyProfile, error)
+	Upsert(profile *models.BeautyProfile) error
+	DeleteByUserID(userID uint) (bool, error)
+}
+
+type beautyProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewBeautyProfileRepository(db *gorm.DB) BeautyProfileRepository {
+	return &beautyProfileRepository{db: db}
+}
+
+func (r *beautyProfileRepository) FindByUserID(userID uint) (*models.BeautyProfile, error) {
+	var profile models.BeautyProfile
+	err := r.db.Where("user_id = ?", userID).First(&profile).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *beautyProfileRepository) Upsert(profile *models.BeautyProfile) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"skin_type", "skin_tone", "hair_type", "concerns", "allergies", "updated_at",
+		}),
+	}).Create(profile).Error
+}
+
+func (r *beautyProfileRepository) DeleteByUserID(userID uint) (bool, error) {
+	result := r.db.Where("user_id = ?", userID).Delete(&models.BeautyProfile{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"time"
+
+	"1kosmetika-marketplace-backend/models"
+
+	"gorm.io/gorm"
+)
+
+type CartReminderRepository interface {
+	Create(reminder *models.CartReminder) error
+	ExistsForCartVersion(cartID uint, cartUpdatedAt time.Time) (bool, error)
+	MarkRecovered(userID, orderID uint, since time.Time) error
+}
+
+type cartReminderRepository struct {
+	db *gorm.DB
+}
+
+func NewCartReminderRepository(db *gorm.DB) CartReminderRepository {
+	return &cartReminderRepository{db: db}
+}
+
+func (r *cartReminderRepository) Create(reminder *models.CartReminder) error {
+	return r.db.Create(reminder).Error
+}
+
+func (r *cartReminderRepository) ExistsForCartVersion(cartID uint, cartUpdatedAt time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.CartReminder{}).
+		Where("cart_id = ? AND cart_updated_at >= ?", cartID, cartUpdatedAt).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Заказ после напоминания считается восстановленной корзиной: отмечается последнее
+// невосстановленное напоминание пользователя, отправленное не раньше since.
+func (r *cartReminderRepository) MarkRecovered(userID, orderID uint, since time.Time) error {
+	var reminder models.CartReminder
+	err := r.db.Where("user_id = ? AND recovered_order_id IS NULL AND sent_at >= ?", userID, since).
+		Order("sent_at DESC").First(&reminder).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	reminder.RecoveredOrderID = &orderID
+	reminder.RecoveredAt = &now
+	return r.db.Save(&reminder).Error
+}
@@ -2,13 +2,18 @@
 package repositories
 
 import (
+	"time"
+
 	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
 
 	"gorm.io/gorm"
 )
 
 type CartRepository interface {
 	FindByUserID(userID uint) (*models.Cart, error)
+	FindByGuestToken(token string) (*models.Cart, error)
+	DeleteCart(cartID uint) error
 	Create(cart *models.Cart) error
 	Update(cart *models.Cart) error
 
@@ -21,7 +26,14 @@ type CartRepository interface {
 	DeleteCartItemOwnedByUser(userID, itemID uint) (bool, error)
 
 	ClearCart(cartID uint) error
+	ReplaceCartItems(cartID uint, items []models.CartItem) error
 	GetCartWithItems(userID uint) (*models.Cart, error)
+	DeleteItemsByProduct(productID uint) (int64, error)
+	RepriceProduct(productID uint, unitPrice money.Amount) error
+	FindUserIDsByProduct(productID uint) ([]uint, error)
+
+	FindAbandoned(updatedBefore, updatedAfter time.Time) ([]models.Cart, error)
+	DeleteStaleGuestCarts(updatedBefore time.Time) (int64, error)
 }
 
 type cartRepository struct {
@@ -41,6 +53,24 @@ func (r *cartRepository) FindByUserID(userID uint) (*models.Cart, error) {
 	return &cart, nil
 }
 
+func (r *cartRepository) FindByGuestToken(token string) (*models.Cart, error) {
+	var cart models.Cart
+	err := r.db.Preload("Items.Product").Where("guest_token = ? AND user_id IS NULL", token).First(&cart).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func (r *cartRepository) DeleteCart(cartID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("cart_id = ?", cartID).Delete(&models.CartItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Cart{}, cartID).Error
+	})
+}
+
 func (r *cartRepository) Create(cart *models.Cart) error {
 	return r.db.Create(cart).Error
 }
@@ -67,12 +97,26 @@ func (r *cartRepository) FindCartItemByID(itemID uint) (*models.CartItem, error)
 	return &item, nil
 }
 
+// Позиции хранятся в отдельной таблице, поэтому updated_at корзины
+// обновляется явно — по нему определяются брошенные корзины.
+func (r *cartRepository) touch(cartID uint) {
+	r.db.Model(&models.Cart{}).Where("id = ?", cartID).Update("updated_at", time.Now())
+}
+
 func (r *cartRepository) CreateCartItem(item *models.CartItem) error {
-	return r.db.Create(item).Error
+	if err := r.db.Create(item).Error; err != nil {
+		return err
+	}
+	r.touch(item.CartID)
+	return nil
 }
 
 func (r *cartRepository) UpdateCartItem(item *models.CartItem) error {
-	return r.db.Save(item).Error
+	if err := r.db.Omit("Product").Save(item).Error; err != nil {
+		return err
+	}
+	r.touch(item.CartID)
+	return nil
 }
 
 func (r *cartRepository) DeleteCartItem(itemID uint) error {
@@ -96,7 +140,31 @@ func (r *cartRepository) DeleteCartItemOwnedByUser(userID, itemID uint) (bool, e
 }
 
 func (r *cartRepository) ClearCart(cartID uint) error {
-	return r.db.Where("cart_id = ?", cartID).Delete(&models.CartItem{}).Error
+	if err := r.db.Where("cart_id = ?", cartID).Delete(&models.CartItem{}).Error; err != nil {
+		return err
+	}
+	r.touch(cartID)
+	return nil
+}
+
+func (r *cartRepository) ReplaceCartItems(cartID uint, items []models.CartItem) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("cart_id = ?", cartID).Delete(&models.CartItem{}).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		for i := range items {
+			items[i].CartID = cartID
+		}
+		return tx.Omit("Product").Create(&items).Error
+	})
+	if err != nil {
+		return err
+	}
+	r.touch(cartID)
+	return nil
 }
 
 func (r *cartRepository) GetCartWithItems(userID uint) (*models.Cart, error) {
@@ -107,3 +175,51 @@ func (r *cartRepository) GetCartWithItems(userID uint) (*models.Cart, error) {
 	}
 	return &cart, nil
 }
+
+// Убирает товар из всех корзин (например, при удалении товара).
+func (r *cartRepository) DeleteItemsByProduct(productID uint) (int64, error) {
+	res := r.db.Where("product_id = ?", productID).Delete(&models.CartItem{})
+	return res.RowsAffected, res.Error
+}
+
+func (r *cartRepository) RepriceProduct(productID uint, unitPrice money.Amount) error {
+	return r.db.Model(&models.CartItem{}).
+		Where("product_id = ?", productID).
+		Updates(map[string]interface{}{
+			"unit_price": unitPrice,
+			"price":      gorm.Expr("? * quantity", unitPrice),
+		}).Error
+}
+
+func (r *cartRepository) FindUserIDsByProduct(productID uint) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&models.Cart{}).
+		Joins("JOIN cart_items ON cart_items.cart_id = carts.id").
+		Where("cart_items.product_id = ? AND carts.user_id IS NOT NULL", productID).
+		Distinct().Pluck("carts.user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// Корзины пользователей с позициями, не менявшиеся в окне (updatedAfter, updatedBefore].
+func (r *cartRepository) FindAbandoned(updatedBefore, updatedAfter time.Time) ([]models.Cart, error) {
+	var carts []models.Cart
+	err := r.db.Preload("Items.Product").
+		Where("user_id IS NOT NULL AND updated_at <= ? AND updated_at > ?", updatedBefore, updatedAfter).
+		Where("EXISTS (SELECT 1 FROM cart_items WHERE cart_items.cart_id = carts.id)").
+		Find(&carts).Error
+	return carts, err
+}
+
+func (r *cartRepository) DeleteStaleGuestCarts(updatedBefore time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		stale := tx.Model(&models.Cart{}).Select("id").Where("user_id IS NULL AND updated_at < ?", updatedBefore)
+		if err := tx.Where("cart_id IN (?)", stale).Delete(&models.CartItem{}).Error; err != nil {
+			return err
+		}
+		res := tx.Where("user_id IS NULL AND updated_at < ?", updatedBefore).Delete(&models.Cart{})
+		deleted = res.RowsAffected
+		return res.Error
+	})
+	return deleted, err
+}
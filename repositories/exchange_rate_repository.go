@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"1kosmetika-marketplace-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExchangeRateRepository interface {
+	FindAll() ([]models.ExchangeRate, error)
+	FindByCurrency(currency string) (*models.ExchangeRate, error)
+	Upsert(rate *models.ExchangeRate) error
+	Delete(currency string) (bool, error)
+}
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+func (r *exchangeRateRepository) FindAll() ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	err := r.db.Order("currency").Find(&rates).Error
+	return rates, err
+}
+
+func (r *exchangeRateRepository) FindByCurrency(currency string) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	err := r.db.Where("currency = ?", currency).First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *exchangeRateRepository) Upsert(rate *models.ExchangeRate) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(rate).Error
+}
+
+func (r *exchangeRateRepository) Delete(currency string) (bool, error) {
+	result := r.db.Where("currency = ?", currency).Delete(&models.ExchangeRate{})
+	return result.RowsAffected > 0, result.Error
+}
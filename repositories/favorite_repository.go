@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
 
 	"gorm.io/gorm"
 )
@@ -12,6 +13,12 @@ type FavoriteRepository interface {
 	FindByUserAndProduct(userID, productID uint) (*models.Favorite, error)
 	FindByUserID(userID uint) ([]models.Favorite, error)
 	Exists(userID, productID uint) (bool, error)
+	FindUserIDsByProduct(productID uint) ([]uint, error)
+	DeleteFromWishlist(wishlistID, productID uint) (bool, error)
+	ExistsInWishlist(wishlistID, productID uint) (bool, error)
+	UpdateAlerts(userID, productID uint, fields map[string]interface{}) (bool, error)
+	FindWithAlerts() ([]models.Favorite, error)
+	UpdateAlertState(id uint, price money.Amount, inStock bool) error
 }
 
 type favoriteRepository struct {
@@ -41,7 +48,9 @@ func (r *favoriteRepository) FindByUserAndProduct(userID, productID uint) (*mode
 
 func (r *favoriteRepository) FindByUserID(userID uint) ([]models.Favorite, error) {
 	var favorites []models.Favorite
-	err := r.db.Preload("Product").Where("user_id = ?", userID).Order("created_at DESC").Find(&favorites).Error
+	err := r.db.Preload("Product").
+		Where("user_id = ? AND product_id IN (SELECT id FROM products WHERE deleted_at IS NULL)", userID).
+		Order("created_at DESC").Find(&favorites).Error
 	return favorites, err
 }
 
@@ -49,4 +58,40 @@ func (r *favoriteRepository) Exists(userID, productID uint) (bool, error) {
 	var count int64
 	err := r.db.Model(&models.Favorite{}).Where("user_id = ? AND product_id = ?", userID, productID).Count(&count).Error
 	return count > 0, err
-}
\ No newline at end of file
+}
+
+func (r *favoriteRepository) FindUserIDsByProduct(productID uint) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&models.Favorite{}).Where("product_id = ?", productID).Distinct().Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+func (r *favoriteRepository) DeleteFromWishlist(wishlistID, productID uint) (bool, error) {
+	result := r.db.Where("wishlist_id = ? AND product_id = ?", wishlistID, productID).Delete(&models.Favorite{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *favoriteRepository) ExistsInWishlist(wishlistID, productID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Favorite{}).Where("wishlist_id = ? AND product_id = ?", wishlistID, productID).Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateAlerts меняет настройки уведомлений товара во всех списках пользователя.
+func (r *favoriteRepository) UpdateAlerts(userID, productID uint, fields map[string]interface{}) (bool, error) {
+	result := r.db.Model(&models.Favorite{}).Where("user_id = ? AND product_id = ?", userID, productID).Updates(fields)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *favoriteRepository) FindWithAlerts() ([]models.Favorite, error) {
+	var favorites []models.Favorite
+	err := r.db.Preload("Product").
+		Where("(price_drop_alert OR stock_alert) AND product_id IN (SELECT id FROM products WHERE deleted_at IS NULL)").
+		Order("user_id, product_id").Find(&favorites).Error
+	return favorites, err
+}
+
+func (r *favoriteRepository) UpdateAlertState(id uint, price money.Amount, inStock bool) error {
+	return r.db.Model(&models.Favorite{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"alert_price": price, "alert_in_stock": inStock}).Error
+}
@@ -0,0 +1,235 @@
+package repositories
+
+import (
+	"strings"
+
+	"1kosmetika-marketplace-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Колонки признаков для фильтра free_from.
+var ingredientFlagColumns = map[string]string{
+	models.IngredientFlagAllergen:  "is_allergen",
+	models.IngredientFlagFragrance: "is_fragrance",
+	models.IngredientFlagParaben:   "is_paraben",
+	models.IngredientFlagSulfate:   "is_sulfate",
+	models.IngredientFlagSilicone:  "is_silicone",
+}
+
+type IngredientRepository interface {
+	FindAll(search string, page, limit int) ([]models.Ingredient, int64, error)
+	FindByID(id uint) (*models.Ingredient, error)
+	FindByIDs(ids []uint) ([]models.Ingredient, error)
+	// FindByName ищет по INCI или бытовому названию без учёта регистра.
+	FindByName(name string) (*models.Ingredient, error)
+	Create(ingredient *models.Ingredient) error
+	Update(ingredient *models.Ingredient) error
+	Delete(id uint) error
+	// ReplaceProductIngredients задаёт состав товара в указанном порядке и
+	// обновляет текстовый состав products.ingredients.
+	ReplaceProductIngredients(productID uint, ingredientIDs []uint, text string) error
+	FindProductIngredients(productID uint) ([]models.Ingredient, error)
+	// FindIngredientsByProducts — составы нескольких товаров: product_id → ингредиенты.
+	FindIngredientsByProducts(productIDs []uint) (map[uint][]models.Ingredient, error)
+}
+
+type ingredientRepository struct {
+	db *gorm.DB
+}
+
+func NewIngredientRepository(db *gorm.DB) IngredientRepository {
+	return &ingredientRepository{db: db}
+}
+
+func (r *ingredientRepository) FindAll(search string, page, limit int) ([]models.Ingredient, int64, error) {
+	var ingredients []models.Ingredient
+	var total int64
+
+	query := r.db.Model(&models.Ingredient{})
+	if search != "" {
+		pattern := "%" + search + "%"
+		query = query.Where("inci_name ILIKE ? OR common_names::text ILIKE ?", pattern, pattern)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("inci_name").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&ingredients).Error
+	return ingredients, total, err
+}
+
+func (r *ingredientRepository) FindByID(id uint) (*models.Ingredient, error) {
+	var ingredient models.Ingredient
+	if err := r.db.First(&ingredient, id).Error; err != nil {
+		return nil, err
+	}
+	return &ingredient, nil
+}
+
+func (r *ingredientRepository) FindByIDs(ids []uint) ([]models.Ingredient, error) {
+	var ingredients []models.Ingredient
+	err := r.db.Where("id IN ?", ids).Find(&ingredients).Error
+	return ingredients, err
+}
+
+func (r *ingredientRepository) FindByName(name string) (*models.Ingredient, error) {
+	var ingredient models.Ingredient
+	name = strings.ToLower(strings.TrimSpace(name))
+	err := r.db.
+		Where("LOWER(inci_name) = ?", name).
+		Or("EXISTS (SELECT 1 FROM jsonb_array_elements_text(common_names) n WHERE LOWER(n) = ?)", name).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "LOWER(inci_name) = ? DESC",
+			Vars:               []interface{}{name},
+			WithoutParentheses: true,
+		}}).
+		First(&ingredient).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ingredient, nil
+}
+
+func (r *ingredientRepository) Create(ingredient *models.Ingredient) error {
+	return r.db.Create(ingredient).Error
+}
+
+func (r *ingredientRepository) Update(ingredient *models.Ingredient) error {
+	return r.db.Save(ingredient).Error
+}
+
+func (r *ingredientRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("ingredient_id = ?", id).Delete(&models.ProductIngredient{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Ingredient{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *ingredientRepository) ReplaceProductIngredients(productID uint, ingredientIDs []uint, text string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&models.ProductIngredient{}).Error; err != nil {
+			return err
+		}
+		if len(ingredientIDs) > 0 {
+			links := make([]models.ProductIngredient, len(ingredientIDs))
+			for i, id := range ingredientIDs {
+				links[i] = models.ProductIngredient{ProductID: productID, IngredientID: id, Position: i + 1}
+			}
+			if err := tx.Create(&links).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Product{}).Where("id = ?", productID).
+			UpdateColumn("ingredients", text).Error
+	})
+}
+
+func (r *ingredientRepository) FindProductIngredients(productID uint) ([]models.Ingredient, error) {
+	var ingredients []models.Ingredient
+	err := r.db.
+		Joins("JOIN product_ingredients pi ON pi.ingredient_id = ingredients.id").
+		Where("pi.product_id = ?", productID).
+		Order("pi.position").
+		Find(&ingredients).Error
+	return ingredients, err
+}
+
+func (r *ingredientRepository) FindIngredientsByProducts(productIDs []uint) (map[uint][]models.Ingredient, error) {
+	result := make(map[uint][]models.Ingredient)
+	if len(productIDs) == 0 {
+		return result, nil
+	}
+	var links []models.ProductIngredient
+	if err := r.db.Where("product_id IN ?", productIDs).Order("product_id, position").Find(&links).Error; err != nil {
+		return nil, err
+	}
+	if len(links) == 0 {
+		return result, nil
+	}
+
+	ids := make([]uint, 0, len(links))
+	for _, link := range links {
+		ids = append(ids, link.IngredientID)
+	}
+	ingredients, err := r.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Ingredient, len(ingredients))
+	for _, ingredient := range ingredients {
+		byID[ingredient.ID] = ingredient
+	}
+	for _, link := range links {
+		if ingredient, ok := byID[link.IngredientID]; ok {
+			result[link.ProductID] = append(result[link.ProductID], ingredient)
+		}
+	}
+	return result, nil
+}
+
+// ingredientMatch — условие «в составе товара есть ингредиент»: по связанным
+// ингредиентам (INCI и бытовые названия) или по текстовому составу, если
+// товар ещё не размечен.
+const ingredientMatch = `(products.ingredients ILIKE ? OR EXISTS (
+	SELECT 1 FROM product_ingredients pi JOIN ingredients i ON i.id = pi.ingredient_id
+	WHERE pi.product_id = products.id AND (i.inci_name ILIKE ? OR i.common_names::text ILIKE ?)))`
+
+func withIngredient(query *gorm.DB, name string) *gorm.DB {
+	pattern := "%" + name + "%"
+	return query.Where(ingredientMatch, pattern, pattern, pattern)
+}
+
+func withoutIngredient(query *gorm.DB, name string) *gorm.DB {
+	pattern := "%" + name + "%"
+	return query.Where("NOT "+ingredientMatch, pattern, pattern, pattern)
+}
+
+// inciEntry приводит компонент состава к виду, в котором его сравнивает
+// Product.FlagAllergens: нижний регистр, одиночные пробелы, без точки в конце.
+func inciEntry(column string) string {
+	return `BTRIM(RTRIM(LOWER(BTRIM(regexp_replace(` + column + `, '\s+', ' ', 'g'))), '.'))`
+}
+
+// withoutAllergen исключает товары, где аллерген — целый компонент текстового
+// состава или INCI/бытовое название связанного ингредиента. В отличие от
+// withoutIngredient, «paraben» не отсекает «methylparaben».
+func withoutAllergen(query *gorm.DB, allergen string) *gorm.DB {
+	allergen = models.NormalizeINCIEntry(allergen)
+	if allergen == "" {
+		return query
+	}
+	return query.Where(`NOT EXISTS (
+		SELECT 1 FROM regexp_split_to_table(products.ingredients, ',') AS part
+		WHERE `+inciEntry("part")+` = ?
+	) AND NOT EXISTS (
+		SELECT 1 FROM product_ingredients pi JOIN ingredients i ON i.id = pi.ingredient_id
+		WHERE pi.product_id = products.id AND (`+inciEntry("i.inci_name")+` = ? OR EXISTS (
+			SELECT 1 FROM jsonb_array_elements_text(i.common_names) AS common_name
+			WHERE `+inciEntry("common_name")+` = ?)))`,
+		allergen, allergen, allergen)
+}
+
+// withoutIngredientFlag — «без парабенов» и т.п.; неизвестный признак
+// отсекается при разборе запроса.
+func withoutIngredientFlag(query *gorm.DB, flag string) *gorm.DB {
+	column, ok := ingredientFlagColumns[flag]
+	if !ok {
+		return query
+	}
+	return query.Where(`NOT EXISTS (
+		SELECT 1 FROM product_ingredients pi JOIN ingredients i ON i.id = pi.ingredient_id
+		WHERE pi.product_id = products.id AND i.` + column + `)`)
+}
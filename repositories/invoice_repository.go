@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"fmt"
+
+	"1kosmetika-marketplace-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InvoiceRepository interface {
+	FindByOrderID(orderID uint) (*models.Invoice, error)
+	CreateForOrder(orderID uint, build func(seq int64) (*models.Invoice, error)) (*models.Invoice, error)
+}
+
+type invoiceRepository struct {
+	db *gorm.DB
+}
+
+func NewInvoiceRepository(db *gorm.DB) InvoiceRepository {
+	return &invoiceRepository{db: db}
+}
+
+func (r *invoiceRepository) FindByOrderID(orderID uint) (*models.Invoice, error) {
+	var invoice models.Invoice
+	err := r.db.Where("order_id = ?", orderID).First(&invoice).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// CreateForOrder выдаёт следующий номер из invoice_counter и сохраняет счёт.
+// Строка заказа блокируется, поэтому параллельные запросы не создадут два счёта.
+// Счётчик увеличивается в той же транзакции: если PDF или запись не удались,
+// номер откатывается вместе с ней и в нумерации не остаётся пропусков.
+func (r *invoiceRepository) CreateForOrder(orderID uint, build func(seq int64) (*models.Invoice, error)) (*models.Invoice, error) {
+	var invoice *models.Invoice
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var order models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&order, orderID).Error; err != nil {
+			return err
+		}
+
+		var existing models.Invoice
+		err := tx.Where("order_id = ?", orderID).First(&existing).Error
+		if err == nil {
+			invoice = &existing
+			return nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		var seq int64
+		if err := tx.Raw("UPDATE invoice_counter SET last_number = last_number + 1 WHERE id = 1 RETURNING last_number").Scan(&seq).Error; err != nil {
+			return err
+		}
+		if seq == 0 {
+			return fmt.Errorf("invoice counter is not initialized")
+		}
+		created, err := build(seq)
+		if err != nil {
+			return err
+		}
+		created.OrderID = orderID
+		if err := tx.Create(created).Error; err != nil {
+			return err
+		}
+		invoice = created
+		return nil
+	})
+	return invoice, err
+}
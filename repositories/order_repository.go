@@ -1,18 +1,36 @@
 package repositories
 
 import (
+	"errors"
+	"time"
+
 	"1kosmetika-marketplace-backend/models"
 
 	"gorm.io/gorm"
 )
 
+var (
+	ErrInsufficientStock  = errors.New("not enough stock available")
+	ErrOrderNotCancelable = errors.New("order can no longer be cancelled")
+)
+
 type OrderRepository interface {
 	Create(order *models.Order) error
 	CreateOrderProducts(items []models.OrderProduct) error
+	CreateWithItems(order *models.Order, items []models.OrderProduct) error
 	FindByID(id uint) (*models.Order, error)
 	FindByUserID(userID uint) ([]models.Order, error)
 	FindAll() ([]models.Order, error)
 	Update(order *models.Order) error
+	UpdateStatus(orderID uint, status string) error
+	FindItems(orderID uint) ([]models.OrderProduct, error)
+	Cancel(orderID uint, reason, paymentStatus string, cancelledAt time.Time) error
+	HasCompletedPurchase(userID, productID uint) (bool, error)
+}
+
+// Удалённые товары (soft delete) должны оставаться видимыми в истории заказов.
+func withProducts(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
 }
 
 type orderRepository struct {
@@ -34,24 +52,122 @@ func (r *orderRepository) CreateOrderProducts(items []models.OrderProduct) error
 	return r.db.Create(&items).Error
 }
 
+// Заказ и его строки сохраняются в одной транзакции. Связь Products не пишется
+// через GORM: order_products заполняется строками с количеством и ценой.
+// Остаток списывается условным UPDATE, чтобы параллельные заказы не увели его в минус.
+func (r *orderRepository) CreateWithItems(order *models.Order, items []models.OrderProduct) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			result := tx.Model(&models.Product{}).
+				Where("id = ? AND stock >= ?", item.ProductID, item.Quantity).
+				Update("stock", gorm.Expr("stock - ?", item.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrInsufficientStock
+			}
+		}
+
+		order.StockReserved = true
+		if err := tx.Omit("Products").Create(order).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		for i := range items {
+			items[i].OrderID = order.ID
+		}
+		return tx.Create(&items).Error
+	})
+}
+
 func (r *orderRepository) FindByID(id uint) (*models.Order, error) {
 	var order models.Order
-	err := r.db.Preload("Products").Preload("User").First(&order, id).Error
+	err := r.db.Preload("Products", withProducts).Preload("User").First(&order, id).Error
 	return &order, err
 }
 
 func (r *orderRepository) FindByUserID(userID uint) ([]models.Order, error) {
 	var orders []models.Order
-	err := r.db.Preload("Products").Where("user_id = ?", userID).Find(&orders).Error
+	err := r.db.Preload("Products", withProducts).Where("user_id = ?", userID).Find(&orders).Error
 	return orders, err
 }
 
 func (r *orderRepository) FindAll() ([]models.Order, error) {
 	var orders []models.Order
-	err := r.db.Preload("Products").Preload("User").Find(&orders).Error
+	err := r.db.Preload("Products", withProducts).Preload("User").Find(&orders).Error
 	return orders, err
 }
 
 func (r *orderRepository) Update(order *models.Order) error {
 	return r.db.Save(order).Error
 }
+
+func (r *orderRepository) FindItems(orderID uint) ([]models.OrderProduct, error) {
+	var items []models.OrderProduct
+	err := r.db.Where("order_id = ?", orderID).Order("product_id").Find(&items).Error
+	return items, err
+}
+
+func (r *orderRepository) UpdateStatus(orderID uint, status string) error {
+	return r.db.Model(&models.Order{}).Where("id = ?", orderID).Update("status", status).Error
+}
+
+// Cancel отменяет заказ и возвращает товары на склад, если они были списаны
+// при оформлении. Статус проверяется в том же UPDATE, поэтому повторная
+// отмена не вернёт остаток дважды.
+func (r *orderRepository) Cancel(orderID uint, reason, paymentStatus string, cancelledAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Order{}).
+			Where("id = ? AND status IN ?", orderID, models.CancellableOrderStatuses).
+			Updates(map[string]interface{}{
+				"status":         models.OrderStatusCancelled,
+				"cancel_reason":  reason,
+				"cancelled_at":   cancelledAt,
+				"payment_status": paymentStatus,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrOrderNotCancelable
+		}
+
+		var order models.Order
+		if err := tx.Select("id", "stock_reserved").First(&order, orderID).Error; err != nil {
+			return err
+		}
+		if !order.StockReserved {
+			return nil
+		}
+		if err := tx.Model(&order).UpdateColumn("stock_reserved", false).Error; err != nil {
+			return err
+		}
+
+		var items []models.OrderProduct
+		if err := tx.Where("order_id = ?", orderID).Find(&items).Error; err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := tx.Unscoped().Model(&models.Product{}).
+				Where("id = ?", item.ProductID).
+				Update("stock", gorm.Expr("stock + ?", item.Quantity)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// HasCompletedPurchase — есть ли у пользователя выполненный заказ с товаром.
+func (r *orderRepository) HasCompletedPurchase(userID, productID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.OrderProduct{}).
+		Joins("JOIN orders ON orders.id = order_products.order_id").
+		Where("orders.user_id = ? AND orders.status = ? AND order_products.product_id = ?",
+			userID, models.OrderStatusCompleted, productID).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"1kosmetika-marketplace-backend/models"
+
+	"gorm.io/gorm"
+)
+
+type PriceHistoryRepository interface {
+	Create(entry *models.PriceHistory) error
+	FindByProductID(productID uint) ([]models.PriceHistory, error)
+}
+
+type priceHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceHistoryRepository(db *gorm.DB) PriceHistoryRepository {
+	return &priceHistoryRepository{db: db}
+}
+
+func (r *priceHistoryRepository) Create(entry *models.PriceHistory) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *priceHistoryRepository) FindByProductID(productID uint) ([]models.PriceHistory, error) {
+	var history []models.PriceHistory
+	err := r.db.Where("product_id = ?", productID).Order("created_at DESC").Find(&history).Error
+	return history, err
+}
@@ -1,9 +1,15 @@
 package repositories
 
 import (
+	"encoding/json"
+	"strings"
+	"time"
+
 	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProductRepository interface {
@@ -18,15 +24,60 @@ type ProductRepository interface {
 	FindWithFilters(filter ProductFilter, page, limit int) ([]models.Product, int64, error)
 	GetCategories() ([]string, error)
 	GetBrands() ([]string, error)
+
+	FindBySlug(slug string) (*models.Product, error)
+	SlugExists(slug string, excludeID uint) (bool, error)
+	SKUExists(sku string, excludeID uint) (bool, error)
+	FindSlugRedirect(slug string) (*models.ProductSlugRedirect, error)
+	SaveSlugRedirect(slug string, productID uint) error
+	DeleteSlugRedirect(slug string) error
+
+	PublishScheduled(now time.Time) (int64, error)
+	UnpublishScheduled(now time.Time) (int64, error)
+	FindSalesToStart(now time.Time) ([]models.Product, error)
+	FindSalesToEnd(now time.Time) ([]models.Product, error)
+	IncrementStock(id uint, quantity int) error
+	IncrementViewCount(id uint) error
 }
 
 
 type ProductFilter struct {
 	Category string  `json:"category"`
 	Brand    string  `json:"brand"`
-	MinPrice float64 `json:"min_price"`
-	MaxPrice float64 `json:"max_price"`
+	MinPrice money.Amount `json:"min_price"`
+	MaxPrice money.Amount `json:"max_price"`
 	Search   string  `json:"search"`
+	// Пусто — только активные товары (публичный каталог), "all" — все статусы (админка).
+	Status string `json:"status,omitempty"`
+	// Минимальный средний рейтинг, 0 — без фильтра.
+	MinRating float64 `json:"min_rating,omitempty"`
+	// newest, price_asc, price_desc, rating, suitability; пусто — порядок по умолчанию.
+	Sort string `json:"sort,omitempty"`
+
+	// Подбор по типу кожи/волос и проблеме; товары без указанных типов подходят всем.
+	SkinType string `json:"skin_type,omitempty"`
+	HairType string `json:"hair_type,omitempty"`
+	Concern  string `json:"concern,omitempty"`
+	// Ингредиенты (INCI или бытовое название): в составе должны быть все
+	// IncludeIngredients и ни одного из ExcludeIngredients.
+	IncludeIngredients []string `json:"include_ingredients,omitempty"`
+	ExcludeIngredients []string `json:"exclude_ingredients,omitempty"`
+	// FreeFrom — признаки ингредиентов (models.IngredientFlags), которых не должно быть в составе.
+	FreeFrom []string `json:"free_from,omitempty"`
+	// ExcludeAllergens — аллергии из профиля; сверяются как в Product.FlagAllergens:
+	// целый компонент состава или название связанного ингредиента.
+	ExcludeAllergens []string `json:"exclude_allergens,omitempty"`
+	// Profile — бьюти-профиль покупателя для сортировки suitability.
+	Profile *models.BeautyProfile `json:"-"`
+}
+
+const ProductSortSuitability = "suitability"
+
+var productSortOrders = map[string]string{
+	"newest":     "created_at DESC, id DESC",
+	"price_asc":  "price ASC, id",
+	"price_desc": "price DESC, id",
+	"rating":     "rating_avg DESC, rating_count DESC, id",
 }
 
 type productRepository struct {
@@ -47,14 +98,20 @@ func (r *productRepository) FindByID(id uint) (*models.Product, error) {
 	return &product, err
 }
 
+func activeProducts(db *gorm.DB) *gorm.DB {
+	return db.Where("products.status = ?", models.ProductStatusActive)
+}
+
 func (r *productRepository) FindAll() ([]models.Product, error) {
 	var products []models.Product
-	err := r.db.Find(&products).Error
+	err := r.db.Scopes(activeProducts).Find(&products).Error
 	return products, err
 }
 
 func (r *productRepository) Update(product *models.Product) error {
-	return r.db.Save(product).Error
+	// Рейтинг ведут только отзывы, просмотры — отдельный счётчик, текст
+	// состава — IngredientService: сохранение карточки их не трогает.
+	return r.db.Omit("RatingAvg", "RatingCount", "ViewCount", "Ingredients").Save(product).Error
 }
 
 func (r *productRepository) Delete(id uint) error {
@@ -75,12 +132,12 @@ func (r *productRepository) FindWithPagination(page, limit int) ([]models.Produc
 	offset := (page - 1) * limit
 	
 
-	if err := r.db.Model(&models.Product{}).Count(&total).Error; err != nil {
+	if err := r.db.Model(&models.Product{}).Scopes(activeProducts).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 	
 
-	err := r.db.Limit(limit).Offset(offset).Find(&products).Error
+	err := r.db.Scopes(activeProducts).Limit(limit).Offset(offset).Find(&products).Error
 	
 	return products, total, err
 }
@@ -93,6 +150,13 @@ func (r *productRepository) FindWithFilters(filter ProductFilter, page, limit in
 	query := r.db.Model(&models.Product{})
 	
 	// Apply filters
+	switch filter.Status {
+	case "":
+		query = query.Scopes(activeProducts)
+	case "all":
+	default:
+		query = query.Where("status = ?", filter.Status)
+	}
 	if filter.Category != "" {
 		query = query.Where("category = ?", filter.Category)
 	}
@@ -109,6 +173,30 @@ func (r *productRepository) FindWithFilters(filter ProductFilter, page, limit in
 		searchPattern := "%" + filter.Search + "%"
 		query = query.Where("name ILIKE ? OR description ILIKE ?", searchPattern, searchPattern)
 	}
+	if filter.MinRating > 0 {
+		query = query.Where("rating_avg >= ?", filter.MinRating)
+	}
+	if filter.SkinType != "" {
+		query = query.Where("(skin_types = '[]'::jsonb OR skin_types @> ?::jsonb)", jsonList(filter.SkinType))
+	}
+	if filter.HairType != "" {
+		query = query.Where("(hair_types = '[]'::jsonb OR hair_types @> ?::jsonb)", jsonList(filter.HairType))
+	}
+	if filter.Concern != "" {
+		query = query.Where("concerns @> ?::jsonb", jsonList(filter.Concern))
+	}
+	for _, ingredient := range filter.IncludeIngredients {
+		query = withIngredient(query, ingredient)
+	}
+	for _, ingredient := range filter.ExcludeIngredients {
+		query = withoutIngredient(query, ingredient)
+	}
+	for _, flag := range filter.FreeFrom {
+		query = withoutIngredientFlag(query, flag)
+	}
+	for _, allergen := range filter.ExcludeAllergens {
+		query = withoutAllergen(query, allergen)
+	}
 
 
 	if err := query.Count(&total).Error; err != nil {
@@ -116,6 +204,12 @@ func (r *productRepository) FindWithFilters(filter ProductFilter, page, limit in
 	}
 	
 
+	if order, ok := productSortOrders[filter.Sort]; ok {
+		query = query.Order(order)
+	} else if filter.Sort == ProductSortSuitability && filter.Profile != nil {
+		query = query.Order(suitabilityOrder(filter.Profile))
+	}
+
 	offset := (page - 1) * limit
 	err := query.Limit(limit).Offset(offset).Find(&products).Error
 	
@@ -125,13 +219,136 @@ func (r *productRepository) FindWithFilters(filter ProductFilter, page, limit in
 
 func (r *productRepository) GetCategories() ([]string, error) {
 	var categories []string
-	err := r.db.Model(&models.Product{}).Distinct().Pluck("category", &categories).Error
+	err := r.db.Model(&models.Product{}).Scopes(activeProducts).Distinct().Pluck("category", &categories).Error
 	return categories, err
 }
 
 
 func (r *productRepository) GetBrands() ([]string, error) {
 	var brands []string
-	err := r.db.Model(&models.Product{}).Distinct().Pluck("brand", &brands).Error
+	err := r.db.Model(&models.Product{}).Scopes(activeProducts).Distinct().Pluck("brand", &brands).Error
 	return brands, err
-}
\ No newline at end of file
+}
+
+
+func (r *productRepository) FindBySlug(slug string) (*models.Product, error) {
+	var product models.Product
+	err := r.db.Where("slug = ?", slug).First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *productRepository) SlugExists(slug string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Product{}).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *productRepository) SKUExists(sku string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Product{}).Where("sku = ? AND id <> ?", sku, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *productRepository) FindSlugRedirect(slug string) (*models.ProductSlugRedirect, error) {
+	var redirect models.ProductSlugRedirect
+	err := r.db.Where("slug = ?", slug).First(&redirect).Error
+	if err != nil {
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// Если старый slug уже перенаправлялся на другой товар — перенаправляем на новый.
+func (r *productRepository) SaveSlugRedirect(slug string, productID uint) error {
+	return r.db.Where(models.ProductSlugRedirect{Slug: slug}).
+		Assign(models.ProductSlugRedirect{ProductID: productID}).
+		FirstOrCreate(&models.ProductSlugRedirect{}).Error
+}
+
+func (r *productRepository) DeleteSlugRedirect(slug string) error {
+	return r.db.Where("slug = ?", slug).Delete(&models.ProductSlugRedirect{}).Error
+}
+
+// Черновики с наступившим publish_at становятся активными.
+func (r *productRepository) PublishScheduled(now time.Time) (int64, error) {
+	res := r.db.Model(&models.Product{}).
+		Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", models.ProductStatusDraft, now).
+		Where("unpublish_at IS NULL OR unpublish_at > ?", now).
+		Updates(map[string]interface{}{"status": models.ProductStatusActive, "publish_at": nil})
+	return res.RowsAffected, res.Error
+}
+
+// Активные товары с наступившим unpublish_at уходят в архив.
+func (r *productRepository) UnpublishScheduled(now time.Time) (int64, error) {
+	res := r.db.Model(&models.Product{}).
+		Where("status = ? AND unpublish_at IS NOT NULL AND unpublish_at <= ?", models.ProductStatusActive, now).
+		Updates(map[string]interface{}{"status": models.ProductStatusArchived, "unpublish_at": nil})
+	return res.RowsAffected, res.Error
+}
+
+func (r *productRepository) FindSalesToStart(now time.Time) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.
+		Where("sale_active = ? AND sale_price > 0 AND sale_starts_at IS NOT NULL AND sale_starts_at <= ?", false, now).
+		Where("sale_ends_at IS NULL OR sale_ends_at > ?", now).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) FindSalesToEnd(now time.Time) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.
+		Where("sale_active = ? AND sale_ends_at IS NOT NULL AND sale_ends_at <= ?", true, now).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) IncrementStock(id uint, quantity int) error {
+	result := r.db.Model(&models.Product{}).Where("id = ?", id).
+		Update("stock", gorm.Expr("stock + ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// IncrementViewCount не меняет updated_at: просмотр не правка карточки.
+func (r *productRepository) IncrementViewCount(id uint) error {
+	return r.db.Model(&models.Product{}).Where("id = ?", id).
+		UpdateColumn("view_count", gorm.Expr("view_count + ?", 1)).Error
+}
+
+func jsonList(values ...string) string {
+	data, _ := json.Marshal(values)
+	return string(data)
+}
+
+// suitabilityOrder ставит выше товары, совпавшие с профилем: тип кожи весит
+// больше типа волос, каждая совпавшая проблема добавляет по одному баллу.
+func suitabilityOrder(profile *models.BeautyProfile) clause.OrderBy {
+	terms := []string{"0"}
+	var vars []interface{}
+	if profile.SkinType != "" {
+		terms = append(terms, "(skin_types @> ?::jsonb)::int * 2")
+		vars = append(vars, jsonList(profile.SkinType))
+	}
+	if profile.HairType != "" {
+		terms = append(terms, "(hair_types @> ?::jsonb)::int")
+		vars = append(vars, jsonList(profile.HairType))
+	}
+	for _, concern := range profile.Concerns {
+		terms = append(terms, "(concerns @> ?::jsonb)::int")
+		vars = append(vars, jsonList(concern))
+	}
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL:                "(" + strings.Join(terms, " + ") + ") DESC, rating_avg DESC, id",
+		Vars:               vars,
+		WithoutParentheses: true,
+	}}
+}
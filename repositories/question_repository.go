@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"1kosmetika-marketplace-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type QuestionRepository interface {
+	CreateQuestion(question *models.ProductQuestion) error
+	FindQuestionByID(id uint) (*models.ProductQuestion, error)
+	UpdateQuestionStatus(id uint, status string) error
+	DeleteQuestion(id uint) error
+	// FindByProduct — одобренные вопросы товара; search ищет в вопросах и ответах.
+	FindByProduct(productID uint, search string, page, limit int) ([]models.ProductQuestion, int64, error)
+	FindByStatus(status string, page, limit int) ([]models.ProductQuestion, int64, error)
+	CreateAnswer(answer *models.ProductAnswer) error
+	FindAnswerByID(id uint) (*models.ProductAnswer, error)
+	AddAnswerVote(answerID, userID uint) (bool, error)
+	RemoveAnswerVote(answerID, userID uint) (bool, error)
+}
+
+type questionRepository struct {
+	db *gorm.DB
+}
+
+func NewQuestionRepository(db *gorm.DB) QuestionRepository {
+	return &questionRepository{db: db}
+}
+
+// Официальные ответы первыми, затем самые полезные.
+func preloadAnswers(db *gorm.DB) *gorm.DB {
+	return db.Preload("Answers", func(db *gorm.DB) *gorm.DB {
+		return db.Order("is_official DESC, upvotes DESC, created_at ASC")
+	}).Preload("Answers.User")
+}
+
+func (r *questionRepository) CreateQuestion(question *models.ProductQuestion) error {
+	return r.db.Omit("User", "Answers").Create(question).Error
+}
+
+func (r *questionRepository) FindQuestionByID(id uint) (*models.ProductQuestion, error) {
+	var question models.ProductQuestion
+	err := preloadAnswers(r.db.Preload("User")).First(&question, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+func (r *questionRepository) UpdateQuestionStatus(id uint, status string) error {
+	return r.db.Model(&models.ProductQuestion{}).Where("id = ?", id).Update("status", status).Error
+}
+
+func (r *questionRepository) DeleteQuestion(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("answer_id IN (SELECT id FROM product_answers WHERE question_id = ?)", id).
+			Delete(&models.AnswerVote{}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("question_id = ?", id).Delete(&models.ProductAnswer{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ProductQuestion{}, id).Error
+	})
+}
+
+func (r *questionRepository) FindByProduct(productID uint, search string, page, limit int) ([]models.ProductQuestion, int64, error) {
+	var questions []models.ProductQuestion
+	var total int64
+
+	query := r.db.Model(&models.ProductQuestion{}).
+		Where("product_id = ? AND status = ?", productID, models.QuestionStatusApproved)
+	if search != "" {
+		pattern := "%" + search + "%"
+		query = query.Where("body ILIKE ? OR EXISTS (SELECT 1 FROM product_answers a WHERE a.question_id = product_questions.id AND a.body ILIKE ?)",
+			pattern, pattern)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := preloadAnswers(query.Preload("User")).
+		Order("answer_count DESC, created_at DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&questions).Error
+	return questions, total, err
+}
+
+func (r *questionRepository) FindByStatus(status string, page, limit int) ([]models.ProductQuestion, int64, error) {
+	var questions []models.ProductQuestion
+	var total int64
+
+	query := r.db.Model(&models.ProductQuestion{}).Where("status = ?", status)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").
+		Order("created_at ASC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&questions).Error
+	return questions, total, err
+}
+
+func (r *questionRepository) CreateAnswer(answer *models.ProductAnswer) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("User").Create(answer).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`
+			UPDATE product_questions SET answer_count = (SELECT COUNT(*) FROM product_answers WHERE question_id = ?)
+			WHERE id = ?`, answer.QuestionID, answer.QuestionID).Error
+	})
+}
+
+func (r *questionRepository) FindAnswerByID(id uint) (*models.ProductAnswer, error) {
+	var answer models.ProductAnswer
+	err := r.db.Preload("User").First(&answer, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &answer, nil
+}
+
+func (r *questionRepository) AddAnswerVote(answerID, userID uint) (bool, error) {
+	added := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.AnswerVote{AnswerID: answerID, UserID: userID})
+		if result.Error != nil {
+			return result.Error
+		}
+		added = result.RowsAffected > 0
+		return recountAnswerVotes(tx, answerID)
+	})
+	return added, err
+}
+
+func (r *questionRepository) RemoveAnswerVote(answerID, userID uint) (bool, error) {
+	removed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("answer_id = ? AND user_id = ?", answerID, userID).Delete(&models.AnswerVote{})
+		if result.Error != nil {
+			return result.Error
+		}
+		removed = result.RowsAffected > 0
+		return recountAnswerVotes(tx, answerID)
+	})
+	return removed, err
+}
+
+func recountAnswerVotes(tx *gorm.DB, answerID uint) error {
+	return tx.Exec(`UPDATE product_answers SET upvotes = (SELECT COUNT(*) FROM answer_votes WHERE answer_id = ?) WHERE id = ?`,
+		answerID, answerID).Error
+}
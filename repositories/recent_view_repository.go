@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"time"
+
+	"1kosmetika-marketplace-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecentViewRepository interface {
+	// Record сохраняет просмотр и оставляет у пользователя keep последних.
+	Record(userID, productID uint, at time.Time, keep int) error
+	FindProductsByUser(userID uint, limit int) ([]models.Product, error)
+	DeleteByUser(userID uint) error
+}
+
+type recentViewRepository struct {
+	db *gorm.DB
+}
+
+func NewRecentViewRepository(db *gorm.DB) RecentViewRepository {
+	return &recentViewRepository{db: db}
+}
+
+func (r *recentViewRepository) Record(userID, productID uint, at time.Time, keep int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"viewed_at"}),
+		}).Create(&models.RecentView{UserID: userID, ProductID: productID, ViewedAt: at}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec(`
+			DELETE FROM recent_views WHERE user_id = ? AND product_id NOT IN (
+				SELECT product_id FROM recent_views WHERE user_id = ? ORDER BY viewed_at DESC LIMIT ?
+			)`, userID, userID, keep).Error
+	})
+}
+
+func (r *recentViewRepository) FindProductsByUser(userID uint, limit int) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Scopes(activeProducts).
+		Joins("JOIN recent_views rv ON rv.product_id = products.id").
+		Where("rv.user_id = ?", userID).
+		Order("rv.viewed_at DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *recentViewRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.RecentView{}).Error
+}
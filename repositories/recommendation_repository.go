@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"1kosmetika-marketplace-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Заказы в этих статусах не считаются покупками для рекомендаций.
+var excludedRecommendationStatuses = []string{models.OrderStatusCancelled, models.OrderStatusRefunded}
+
+type RecommendationRepository interface {
+	FindRelated(productID uint, limit int) ([]models.Product, error)
+	// FindSimilar — товары той же категории или бренда, для товаров без расчёта.
+	FindSimilar(product *models.Product, limit int) ([]models.Product, error)
+	FindForUser(userID uint, limit int) ([]models.Product, error)
+	FindTopRated(limit int) ([]models.Product, error)
+	// RebuildRelated пересчитывает product_relations, оставляя perProduct лучших.
+	RebuildRelated(perProduct int) (int64, error)
+	// RebuildUserRecommendations пересчитывает user_recommendations по
+	// product_relations, поэтому вызывается после RebuildRelated.
+	RebuildUserRecommendations(perUser int) (int64, error)
+}
+
+type recommendationRepository struct {
+	db *gorm.DB
+}
+
+func NewRecommendationRepository(db *gorm.DB) RecommendationRepository {
+	return &recommendationRepository{db: db}
+}
+
+func (r *recommendationRepository) FindRelated(productID uint, limit int) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Scopes(activeProducts).
+		Joins("JOIN product_relations pr ON pr.related_product_id = products.id").
+		Where("pr.product_id = ?", productID).
+		Order("pr.score DESC, products.rating_avg DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *recommendationRepository) FindSimilar(product *models.Product, limit int) ([]models.Product, error) {
+	var products []models.Product
+	// Пустые категория и бренд сходства не дают: иначе все товары без бренда «похожи».
+	if product.Category == "" && product.Brand == "" {
+		return products, nil
+	}
+	err := r.db.Scopes(activeProducts).
+		Where("id <> ? AND ((category = ? AND category <> '') OR (brand = ? AND brand <> ''))", product.ID, product.Category, product.Brand).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "(category = ?) DESC, (brand = ?) DESC, rating_avg DESC",
+			Vars:               []interface{}{product.Category, product.Brand},
+			WithoutParentheses: true,
+		}}).
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *recommendationRepository) FindForUser(userID uint, limit int) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Scopes(activeProducts).
+		Joins("JOIN user_recommendations ur ON ur.product_id = products.id").
+		Where("ur.user_id = ?", userID).
+		Order("ur.score DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *recommendationRepository) FindTopRated(limit int) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Scopes(activeProducts).
+		Order("rating_avg DESC, rating_count DESC, created_at DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *recommendationRepository) RebuildRelated(perProduct int) (int64, error) {
+	var rows int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM product_relations").Error; err != nil {
+			return err
+		}
+		result := tx.Exec(`
+			INSERT INTO product_relations (product_id, related_product_id, score, source, updated_at)
+			SELECT product_id, related_product_id, score, source, NOW() FROM (
+				SELECT c.product_id, c.related_product_id, c.score,
+					CASE WHEN c.score >= 1 THEN ? ELSE ? END AS source,
+					ROW_NUMBER() OVER (PARTITION BY c.product_id ORDER BY c.score DESC, rp.rating_avg DESC, c.related_product_id) AS rn
+				FROM (
+					SELECT product_id, related_product_id, MAX(score) AS score FROM (
+						SELECT a.product_id, b.product_id AS related_product_id, COUNT(DISTINCT a.order_id)::float AS score
+						FROM order_products a
+						JOIN order_products b ON b.order_id = a.order_id AND b.product_id <> a.product_id
+						JOIN orders o ON o.id = a.order_id
+						WHERE o.status NOT IN ?
+						GROUP BY a.product_id, b.product_id
+						UNION ALL
+						SELECT p.id, s.id,
+							CASE WHEN p.category = s.category AND p.category <> '' AND p.brand = s.brand AND p.brand <> '' THEN 0.5
+								WHEN p.category = s.category AND p.category <> '' THEN 0.3
+								ELSE 0.1 END
+						FROM products p
+						JOIN products s ON s.id <> p.id AND (
+							(s.category = p.category AND p.category <> '') OR (s.brand = p.brand AND p.brand <> ''))
+						WHERE p.deleted_at IS NULL AND s.deleted_at IS NULL
+					) candidates
+					GROUP BY product_id, related_product_id
+				) c
+				JOIN products rp ON rp.id = c.related_product_id
+				WHERE rp.status = ? AND rp.deleted_at IS NULL
+			) ranked
+			WHERE rn <= ?`,
+			models.RelationSourceCoPurchase, models.RelationSourceSimilar,
+			excludedRecommendationStatuses, models.ProductStatusActive, perProduct)
+		rows = result.RowsAffected
+		return result.Error
+	})
+	return rows, err
+}
+
+func (r *recommendationRepository) RebuildUserRecommendations(perUser int) (int64, error) {
+	var rows int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM user_recommendations").Error; err != nil {
+			return err
+		}
+		// Купленное весит вдвое больше избранного; уже купленные и
+		// добавленные в избранное товары не рекомендуются.
+		result := tx.Exec(`
+			WITH seeds AS (
+				SELECT o.user_id, op.product_id, 2.0 AS weight
+				FROM orders o
+				JOIN order_products op ON op.order_id = o.id
+				WHERE o.user_id IS NOT NULL AND o.status NOT IN ?
+				UNION ALL
+				SELECT user_id, product_id, 1.0 FROM favorites
+			)
+			INSERT INTO user_recommendations (user_id, product_id, score, updated_at)
+			SELECT user_id, product_id, score, NOW() FROM (
+				SELECT s.user_id, pr.related_product_id AS product_id, SUM(pr.score * s.weight) AS score,
+					ROW_NUMBER() OVER (PARTITION BY s.user_id ORDER BY SUM(pr.score * s.weight) DESC, pr.related_product_id) AS rn
+				FROM seeds s
+				JOIN product_relations pr ON pr.product_id = s.product_id
+				WHERE NOT EXISTS (
+					SELECT 1 FROM seeds seen
+					WHERE seen.user_id = s.user_id AND seen.product_id = pr.related_product_id
+				)
+				GROUP BY s.user_id, pr.related_product_id
+			) ranked
+			WHERE rn <= ?`,
+			excludedRecommendationStatuses, perUser)
+		rows = result.RowsAffected
+		return result.Error
+	})
+	return rows, err
+}
@@ -1,20 +1,67 @@
 package repositories
 
 import (
+	"errors"
+
 	"1kosmetika-marketplace-backend/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrAlreadyReported = errors.New("you have already reported this review")
+
+const (
+	ReviewSortNewest  = "newest"
+	ReviewSortHelpful = "helpful"
+	ReviewSortHighest = "highest"
+	ReviewSortLowest  = "lowest"
 )
 
+// ReviewQuery — фильтры и сортировка отзывов на витрине.
+type ReviewQuery struct {
+	Rating     int // 1–5, 0 — все оценки
+	WithPhotos bool
+	Sort       string // newest, helpful, highest, lowest
+	Page       int
+	Limit      int
+}
+
+func orderReplies(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at ASC")
+}
+
+var reviewSortOrders = map[string]string{
+	ReviewSortNewest:  "created_at DESC, id DESC",
+	ReviewSortHelpful: "helpful_count DESC, created_at DESC, id DESC",
+	ReviewSortHighest: "rating DESC, created_at DESC, id DESC",
+	ReviewSortLowest:  "rating ASC, created_at DESC, id DESC",
+}
+
 type ReviewRepository interface {
 	Create(review *models.Review) error
 	Update(review *models.Review) error
+	ResetReports(reviewID uint) error
 	Delete(reviewID uint) error
 	FindByID(reviewID uint) (*models.Review, error)
 	FindByUserAndProduct(userID, productID uint) (*models.Review, error)
-	FindByProductID(productID uint) ([]models.Review, error)
+	FindByProductID(productID uint, query ReviewQuery) ([]models.Review, int64, error)
 	FindByUserID(userID uint) ([]models.Review, error)
+	FindByStatus(status string, page, limit int) ([]models.Review, int64, error)
+	ReplacePhotos(reviewID uint, urls []string) error
 	GetProductStats(productID uint) (float64, int, error)
+	GetRatingHistogram(productID uint) (map[int]int64, error)
+	// RepairProductRatings пересчитывает сводки всех товаров и возвращает
+	// число исправленных.
+	RepairProductRatings() (int64, error)
+	CreateReply(reply *models.ReviewReply) error
+	DeleteReply(reviewID, replyID uint) (bool, error)
+	// AddReport сохраняет жалобу; при достижении порога опубликованный отзыв
+	// снимается с витрины на повторную модерацию (hidden = true).
+	AddReport(report *models.ReviewReport, threshold int) (hidden bool, err error)
+	FindReports(reviewID uint) ([]models.ReviewReport, error)
+	SaveVote(vote *models.ReviewVote) error
+	DeleteVote(reviewID, userID uint) (bool, error)
 }
 
 type reviewRepository struct {
@@ -26,20 +73,52 @@ func NewReviewRepository(db *gorm.DB) ReviewRepository {
 }
 
 func (r *reviewRepository) Create(review *models.Review) error {
-	return r.db.Create(review).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("User", "Product").Create(review).Error; err != nil {
+			return err
+		}
+		return refreshProductRating(tx, review.ProductID)
+	})
 }
 
 func (r *reviewRepository) Update(review *models.Review) error {
-	return r.db.Save(review).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Счётчики голосов и жалоб меняются только своими методами.
+		if err := tx.Omit("User", "Product", "Photos", "HelpfulCount", "UnhelpfulCount", "ReportCount").Save(review).Error; err != nil {
+			return err
+		}
+		return refreshProductRating(tx, review.ProductID)
+	})
 }
 
 func (r *reviewRepository) Delete(reviewID uint) error {
-	return r.db.Delete(&models.Review{}, reviewID).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("review_id = ?", reviewID).Delete(&models.ReviewPhoto{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("review_id = ?", reviewID).Delete(&models.ReviewVote{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("review_id = ?", reviewID).Delete(&models.ReviewReply{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("review_id = ?", reviewID).Delete(&models.ReviewReport{}).Error; err != nil {
+			return err
+		}
+		var review models.Review
+		if err := tx.Select("id", "product_id").First(&review, reviewID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Review{}, reviewID).Error; err != nil {
+			return err
+		}
+		return refreshProductRating(tx, review.ProductID)
+	})
 }
 
 func (r *reviewRepository) FindByID(reviewID uint) (*models.Review, error) {
 	var review models.Review
-	err := r.db.Preload("User").Preload("Product").First(&review, reviewID).Error
+	err := r.db.Preload("User").Preload("Product").Preload("Photos").Preload("Replies", orderReplies).First(&review, reviewID).Error
 	if err != nil {
 		return nil, err
 	}
@@ -55,18 +134,72 @@ func (r *reviewRepository) FindByUserAndProduct(userID, productID uint) (*models
 	return &review, nil
 }
 
-func (r *reviewRepository) FindByProductID(productID uint) ([]models.Review, error) {
+// FindByProductID — страница опубликованных отзывов для витрины.
+func (r *reviewRepository) FindByProductID(productID uint, q ReviewQuery) ([]models.Review, int64, error) {
 	var reviews []models.Review
-	err := r.db.Preload("User").Where("product_id = ?", productID).Order("created_at DESC").Find(&reviews).Error
-	return reviews, err
+	var total int64
+
+	query := r.db.Model(&models.Review{}).Where("product_id = ? AND status = ?", productID, models.ReviewStatusApproved)
+	if q.Rating > 0 {
+		query = query.Where("rating = ?", q.Rating)
+	}
+	if q.WithPhotos {
+		query = query.Where("EXISTS (SELECT 1 FROM review_photos WHERE review_photos.review_id = reviews.id)")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order, ok := reviewSortOrders[q.Sort]
+	if !ok {
+		order = reviewSortOrders[ReviewSortNewest]
+	}
+	err := query.Preload("User").Preload("Photos").Preload("Replies", orderReplies).
+		Order(order).
+		Offset((q.Page - 1) * q.Limit).Limit(q.Limit).
+		Find(&reviews).Error
+	return reviews, total, err
 }
 
 func (r *reviewRepository) FindByUserID(userID uint) ([]models.Review, error) {
 	var reviews []models.Review
-	err := r.db.Preload("Product").Where("user_id = ?", userID).Order("created_at DESC").Find(&reviews).Error
+	err := r.db.Preload("Product").Preload("Photos").Preload("Replies", orderReplies).Where("user_id = ?", userID).Order("created_at DESC").Find(&reviews).Error
 	return reviews, err
 }
 
+// FindByStatus — очередь модерации: старые отзывы первыми.
+func (r *reviewRepository) FindByStatus(status string, page, limit int) ([]models.Review, int64, error) {
+	var reviews []models.Review
+	var total int64
+
+	query := r.db.Model(&models.Review{}).Where("status = ?", status)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").Preload("Product").Preload("Photos").Preload("Replies", orderReplies).
+		Order("created_at ASC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&reviews).Error
+	return reviews, total, err
+}
+
+func (r *reviewRepository) ReplacePhotos(reviewID uint, urls []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("review_id = ?", reviewID).Delete(&models.ReviewPhoto{}).Error; err != nil {
+			return err
+		}
+		if len(urls) == 0 {
+			return nil
+		}
+		photos := make([]models.ReviewPhoto, 0, len(urls))
+		for _, url := range urls {
+			photos = append(photos, models.ReviewPhoto{ReviewID: reviewID, URL: url})
+		}
+		return tx.Create(&photos).Error
+	})
+}
+
 func (r *reviewRepository) GetProductStats(productID uint) (float64, int, error) {
 	var result struct {
 		AverageRating float64
@@ -74,9 +207,145 @@ func (r *reviewRepository) GetProductStats(productID uint) (float64, int, error)
 	}
 	
 	err := r.db.Model(&models.Review{}).
-		Select("AVG(rating) as average_rating, COUNT(*) as total_count").
-		Where("product_id = ?", productID).
+		Select("COALESCE(AVG(rating), 0) as average_rating, COUNT(*) as total_count").
+		Where("product_id = ? AND status = ?", productID, models.ReviewStatusApproved).
 		Scan(&result).Error
 
 	return result.AverageRating, result.TotalCount, err
-}
\ No newline at end of file
+}
+
+// GetRatingHistogram — число опубликованных отзывов по каждой оценке 1–5.
+func (r *reviewRepository) GetRatingHistogram(productID uint) (map[int]int64, error) {
+	var rows []struct {
+		Rating int
+		Count  int64
+	}
+	err := r.db.Model(&models.Review{}).
+		Select("rating, COUNT(*) as count").
+		Where("product_id = ? AND status = ?", productID, models.ReviewStatusApproved).
+		Group("rating").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	histogram := map[int]int64{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}
+	for _, row := range rows {
+		histogram[row.Rating] = row.Count
+	}
+	return histogram, nil
+}
+
+// SaveVote ставит или меняет голос и пересчитывает счётчики отзыва.
+func (r *reviewRepository) SaveVote(vote *models.ReviewVote) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "review_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"helpful", "updated_at"}),
+		}).Create(vote).Error
+		if err != nil {
+			return err
+		}
+		return recountReviewVotes(tx, vote.ReviewID)
+	})
+}
+
+func (r *reviewRepository) DeleteVote(reviewID, userID uint) (bool, error) {
+	deleted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("review_id = ? AND user_id = ?", reviewID, userID).Delete(&models.ReviewVote{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected > 0
+		return recountReviewVotes(tx, reviewID)
+	})
+	return deleted, err
+}
+
+func recountReviewVotes(tx *gorm.DB, reviewID uint) error {
+	return tx.Exec(`
+		UPDATE reviews SET
+			helpful_count = (SELECT COUNT(*) FROM review_votes WHERE review_id = ? AND helpful),
+			unhelpful_count = (SELECT COUNT(*) FROM review_votes WHERE review_id = ? AND NOT helpful)
+		WHERE id = ?`, reviewID, reviewID, reviewID).Error
+}
+
+// refreshProductRating пересчитывает сводку товара по опубликованным отзывам.
+func refreshProductRating(tx *gorm.DB, productID uint) error {
+	return tx.Exec(`
+		UPDATE products SET
+			rating_avg = COALESCE((SELECT ROUND(AVG(rating)::numeric, 2) FROM reviews WHERE product_id = ? AND status = ?), 0),
+			rating_count = (SELECT COUNT(*) FROM reviews WHERE product_id = ? AND status = ?)
+		WHERE id = ?`,
+		productID, models.ReviewStatusApproved, productID, models.ReviewStatusApproved, productID).Error
+}
+
+func (r *reviewRepository) RepairProductRatings() (int64, error) {
+	result := r.db.Exec(`
+		UPDATE products p SET rating_avg = s.avg, rating_count = s.cnt
+		FROM (
+			SELECT p2.id, COALESCE(ROUND(AVG(rv.rating)::numeric, 2), 0) AS avg, COUNT(rv.id) AS cnt
+			FROM products p2
+			LEFT JOIN reviews rv ON rv.product_id = p2.id AND rv.status = ?
+			GROUP BY p2.id
+		) s
+		WHERE s.id = p.id AND (p.rating_avg <> s.avg OR p.rating_count <> s.cnt)`,
+		models.ReviewStatusApproved)
+	return result.RowsAffected, result.Error
+}
+
+// ResetReports обнуляет счётчик жалоб после решения модератора; сами жалобы остаются.
+func (r *reviewRepository) ResetReports(reviewID uint) error {
+	return r.db.Model(&models.Review{}).Where("id = ?", reviewID).Update("report_count", 0).Error
+}
+
+func (r *reviewRepository) CreateReply(reply *models.ReviewReply) error {
+	return r.db.Create(reply).Error
+}
+
+func (r *reviewRepository) DeleteReply(reviewID, replyID uint) (bool, error) {
+	result := r.db.Where("id = ? AND review_id = ?", replyID, reviewID).Delete(&models.ReviewReply{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *reviewRepository) AddReport(report *models.ReviewReport, threshold int) (bool, error) {
+	hidden := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(report)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAlreadyReported
+		}
+
+		var review models.Review
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "product_id", "status", "report_count").
+			First(&review, report.ReviewID).Error
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{"report_count": review.ReportCount + 1}
+		if threshold > 0 && review.ReportCount+1 >= threshold && review.Status == models.ReviewStatusApproved {
+			updates["status"] = models.ReviewStatusPending
+			hidden = true
+		}
+		if err := tx.Model(&models.Review{}).Where("id = ?", review.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if hidden {
+			return refreshProductRating(tx, review.ProductID)
+		}
+		return nil
+	})
+	return hidden, err
+}
+
+func (r *reviewRepository) FindReports(reviewID uint) ([]models.ReviewReport, error) {
+	var reports []models.ReviewReport
+	err := r.db.Where("review_id = ?", reviewID).Order("created_at").Find(&reports).Error
+	return reports, err
+}
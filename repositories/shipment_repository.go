@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"time"
+
+	"1kosmetika-marketplace-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShipmentRepository interface {
+	Create(shipment *models.Shipment) error
+	Update(shipment *models.Shipment) error
+	FindByID(id uint) (*models.Shipment, error)
+	FindByOrderID(orderID uint) ([]models.Shipment, error)
+	FindByTracking(carrier, trackingNumber string) (*models.Shipment, error)
+	FindToPoll(excludeCarrier string, checkedBefore time.Time, limit int) ([]models.Shipment, error)
+	AddEvent(event *models.ShipmentEvent) (bool, error)
+}
+
+type shipmentRepository struct {
+	db *gorm.DB
+}
+
+func NewShipmentRepository(db *gorm.DB) ShipmentRepository {
+	return &shipmentRepository{db: db}
+}
+
+func withEvents(db *gorm.DB) *gorm.DB {
+	return db.Order("occurred_at ASC, id ASC")
+}
+
+func (r *shipmentRepository) Create(shipment *models.Shipment) error {
+	return r.db.Omit("Events").Create(shipment).Error
+}
+
+func (r *shipmentRepository) Update(shipment *models.Shipment) error {
+	return r.db.Omit("Events").Save(shipment).Error
+}
+
+func (r *shipmentRepository) FindByID(id uint) (*models.Shipment, error) {
+	var shipment models.Shipment
+	err := r.db.Preload("Events", withEvents).First(&shipment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &shipment, nil
+}
+
+func (r *shipmentRepository) FindByOrderID(orderID uint) ([]models.Shipment, error) {
+	var shipments []models.Shipment
+	err := r.db.Preload("Events", withEvents).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&shipments).Error
+	return shipments, err
+}
+
+func (r *shipmentRepository) FindByTracking(carrier, trackingNumber string) (*models.Shipment, error) {
+	var shipment models.Shipment
+	err := r.db.Preload("Events", withEvents).
+		Where("carrier = ? AND tracking_number = ?", carrier, trackingNumber).
+		First(&shipment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &shipment, nil
+}
+
+// Незавершённые отправления, которые давно не проверялись, — самые старые первыми.
+func (r *shipmentRepository) FindToPoll(excludeCarrier string, checkedBefore time.Time, limit int) ([]models.Shipment, error) {
+	var shipments []models.Shipment
+	err := r.db.Preload("Events", withEvents).
+		Where("carrier <> ?", excludeCarrier).
+		Where("status NOT IN ?", []string{models.ShipmentStatusDelivered, models.ShipmentStatusReturned}).
+		Where("last_checked_at IS NULL OR last_checked_at < ?", checkedBefore).
+		Order("last_checked_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&shipments).Error
+	return shipments, err
+}
+
+// AddEvent возвращает false, если такое событие уже было записано.
+func (r *shipmentRepository) AddEvent(event *models.ShipmentEvent) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"1kosmetika-marketplace-backend/models"
+
+	"gorm.io/gorm"
+)
+
+type ShippingRepository interface {
+	CreateMethod(method *models.ShippingMethod) error
+	UpdateMethod(method *models.ShippingMethod) error
+	DeleteMethod(methodID uint) error
+	FindMethodByID(methodID uint) (*models.ShippingMethod, error)
+	FindMethods(activeOnly bool) ([]models.ShippingMethod, error)
+
+	CreateRate(rate *models.ShippingRate) error
+	UpdateRate(rate *models.ShippingRate) error
+	DeleteRate(rateID uint) error
+	FindRateByID(rateID uint) (*models.ShippingRate, error)
+}
+
+type shippingRepository struct {
+	db *gorm.DB
+}
+
+func NewShippingRepository(db *gorm.DB) ShippingRepository {
+	return &shippingRepository{db: db}
+}
+
+func (r *shippingRepository) CreateMethod(method *models.ShippingMethod) error {
+	return r.db.Omit("Rates").Create(method).Error
+}
+
+func (r *shippingRepository) UpdateMethod(method *models.ShippingMethod) error {
+	return r.db.Omit("Rates").Save(method).Error
+}
+
+func (r *shippingRepository) DeleteMethod(methodID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("shipping_method_id = ?", methodID).Delete(&models.ShippingRate{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ShippingMethod{}, methodID).Error
+	})
+}
+
+func (r *shippingRepository) FindMethodByID(methodID uint) (*models.ShippingMethod, error) {
+	var method models.ShippingMethod
+	err := r.db.Preload("Rates").First(&method, methodID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &method, nil
+}
+
+func (r *shippingRepository) FindMethods(activeOnly bool) ([]models.ShippingMethod, error) {
+	var methods []models.ShippingMethod
+	query := r.db.Preload("Rates").Order("id")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Find(&methods).Error
+	return methods, err
+}
+
+func (r *shippingRepository) CreateRate(rate *models.ShippingRate) error {
+	return r.db.Create(rate).Error
+}
+
+func (r *shippingRepository) UpdateRate(rate *models.ShippingRate) error {
+	return r.db.Save(rate).Error
+}
+
+func (r *shippingRepository) DeleteRate(rateID uint) error {
+	return r.db.Delete(&models.ShippingRate{}, rateID).Error
+}
+
+func (r *shippingRepository) FindRateByID(rateID uint) (*models.ShippingRate, error) {
+	var rate models.ShippingRate
+	err := r.db.First(&rate, rateID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
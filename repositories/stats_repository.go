@@ -3,13 +3,14 @@ package repositories
 import (
 	"1kosmetika-marketplace-backend/database"
 	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
 	"time"
 )
 
 type OrderStats struct {
 	UserName       string
 	TotalOrders    int64
-	TotalSpent     float64
+	TotalSpent     money.Amount
 	MostUsedMethod string
 	LastOrderDate  time.Time
 }
@@ -18,19 +19,27 @@ type StatsRepository struct{}
 
 func NewStatsRepository() *StatsRepository { return &StatsRepository{} }
 
+// TotalRevenue включает доставку и налог; TotalShipping и TotalTax — их доли
+// в выручке, NetRevenue — выручка без налога.
 type AdminStats struct {
-	TotalUsers    int64   `json:"total_users"`
-	TotalOrders   int64   `json:"total_orders"`
-	TotalRevenue  float64 `json:"total_revenue"`
-	TotalProducts int64   `json:"total_products"`
-	TotalReviews  int64   `json:"total_reviews"`
+	TotalUsers    int64        `json:"total_users"`
+	TotalOrders   int64        `json:"total_orders"`
+	TotalRevenue  money.Amount `json:"total_revenue"`
+	TotalShipping money.Amount `json:"total_shipping"`
+	TotalTax      money.Amount `json:"total_tax"`
+	NetRevenue    money.Amount `json:"net_revenue"`
+	TotalProducts int64        `json:"total_products"`
+	TotalReviews  int64        `json:"total_reviews"`
 }
 
 type MonthlyStats struct {
-	Month    string  `json:"month"`
-	Orders   int64   `json:"orders"`
-	Revenue  float64 `json:"revenue"`
-	NewUsers int64   `json:"new_users"`
+	Month      string       `json:"month"`
+	Orders     int64        `json:"orders"`
+	Revenue    money.Amount `json:"revenue"`
+	Shipping   money.Amount `json:"shipping"`
+	Tax        money.Amount `json:"tax"`
+	NetRevenue money.Amount `json:"net_revenue"`
+	NewUsers   int64        `json:"new_users"`
 }
 
 type ChartData struct {
@@ -39,29 +48,38 @@ type ChartData struct {
 	SalesByCategory []CategoryStats `json:"sales_by_category"`
 }
 
+// Сортировка популярных товаров: по продажам или по просмотрам карточки.
+const (
+	PopularBySales = "sales"
+	PopularByViews = "views"
+)
+
 type ProductStats struct {
+	ProductID   uint   `json:"product_id"`
 	ProductName string `json:"product_name"`
 	SalesCount  int64  `json:"sales_count"`
+	ViewCount   int64  `json:"view_count"`
 }
 
 type CategoryStats struct {
-	CategoryName string  `json:"category_name"`
-	TotalSales   float64 `json:"total_sales"`
-	OrderCount   int64   `json:"order_count"`
+	CategoryName string       `json:"category_name"`
+	TotalSales   money.Amount `json:"total_sales"`
+	NetSales     money.Amount `json:"net_sales"`
+	OrderCount   int64        `json:"order_count"`
 }
 
 type AdvancedStats struct {
-	AverageOrderValue float64 `json:"average_order_value"`
-	ConversionRate    float64 `json:"conversion_rate"`
-	CustomerLifetime  float64 `json:"customer_lifetime_value"`
-	RepeatCustomers   int64   `json:"repeat_customers"`
+	AverageOrderValue money.Amount `json:"average_order_value"`
+	ConversionRate    float64      `json:"conversion_rate"`
+	CustomerLifetime  money.Amount `json:"customer_lifetime_value"`
+	RepeatCustomers   int64        `json:"repeat_customers"`
 }
 
 type RealTimeStats struct {
-	TodayOrders   int64   `json:"today_orders"`
-	TodayRevenue  float64 `json:"today_revenue"`
-	TodayUsers    int64   `json:"today_users"`
-	PendingOrders int64   `json:"pending_orders"`
+	TodayOrders   int64        `json:"today_orders"`
+	TodayRevenue  money.Amount `json:"today_revenue"`
+	TodayUsers    int64        `json:"today_users"`
+	PendingOrders int64        `json:"pending_orders"`
 }
 
 type UserStats struct {
@@ -84,24 +102,32 @@ type ConversionStats struct {
 }
 
 type RefundStats struct {
-	TotalRefunds   int64   `json:"total_refunds"`
-	RefundedAmount float64 `json:"refunded_amount"`
-	RefundRate     float64 `json:"refund_rate"`
+	TotalRefunds   int64        `json:"total_refunds"`
+	RefundedAmount money.Amount `json:"refunded_amount"`
+	RefundRate     float64      `json:"refund_rate"`
 }
 
 type ProfitStats struct {
-	TotalRevenue float64 `json:"total_revenue"`
-	TotalCost    float64 `json:"total_cost"`
-	NetProfit    float64 `json:"net_profit"`
-	ProfitMargin float64 `json:"profit_margin"`
+	TotalRevenue money.Amount `json:"total_revenue"`
+	TotalCost    money.Amount `json:"total_cost"`
+	NetProfit    money.Amount `json:"net_profit"`
+	ProfitMargin float64      `json:"profit_margin"`
 }
 
+type AbandonedCartStats struct {
+	AbandonedCarts int64        `json:"abandoned_carts"`
+	AbandonedValue money.Amount `json:"abandoned_value"`
+	RemindersSent  int64        `json:"reminders_sent"`
+	RecoveredCarts int64        `json:"recovered_carts"`
+	RecoveredValue money.Amount `json:"recovered_value"`
+	RecoveryRate   float64      `json:"recovery_rate"`
+	ThresholdHours int          `json:"threshold_hours"`
+}
 
 func (r *StatsRepository) GetTrafficStats() (TrafficStats, error) {
 	db := database.DB
 	var stats TrafficStats
 
-
 	_ = db.Raw(`SELECT COUNT(*) FROM traffic_logs`).Scan(&stats.TotalVisits).Error
 	_ = db.Raw(`SELECT COUNT(DISTINCT user_id) FROM traffic_logs WHERE user_id IS NOT NULL`).Scan(&stats.UniqueUsers).Error
 	_ = db.Raw(`SELECT COALESCE(AVG(session_duration),0) FROM traffic_logs`).Scan(&stats.AverageSession).Error
@@ -143,17 +169,19 @@ func (r *StatsRepository) GetProfitStats() (ProfitStats, error) {
 
 	db.Table("orders").Select("COALESCE(SUM(total), 0)").Where("status = 'completed'").Scan(&stats.TotalRevenue)
 
-
 	stats.TotalCost = 0
 
 	stats.NetProfit = stats.TotalRevenue - stats.TotalCost
 	if stats.TotalRevenue > 0 {
-		stats.ProfitMargin = (stats.NetProfit / stats.TotalRevenue) * 100
+		stats.ProfitMargin = float64(stats.NetProfit) / float64(stats.TotalRevenue) * 100
 	}
 
 	return stats, nil
 }
 
+// Отменённые и возвращённые заказы не входят в выручку, доставку и налоги.
+const revenueOrders = "status NOT IN ('cancelled', 'refunded')"
+
 func (r *StatsRepository) GetAdminStats() (AdminStats, error) {
 	db := database.DB
 	var stats AdminStats
@@ -164,10 +192,17 @@ func (r *StatsRepository) GetAdminStats() (AdminStats, error) {
 	if err := db.Table("orders").Count(&stats.TotalOrders).Error; err != nil {
 		return stats, err
 	}
-	if err := db.Table("orders").Select("COALESCE(SUM(total),0)").Scan(&stats.TotalRevenue).Error; err != nil {
+	if err := db.Table("orders").Select("COALESCE(SUM(total),0)").Where(revenueOrders).Scan(&stats.TotalRevenue).Error; err != nil {
+		return stats, err
+	}
+	if err := db.Table("orders").Select("COALESCE(SUM(shipping_cost),0)").Where(revenueOrders).Scan(&stats.TotalShipping).Error; err != nil {
 		return stats, err
 	}
-	if err := db.Table("products").Count(&stats.TotalProducts).Error; err != nil {
+	if err := db.Table("orders").Select("COALESCE(SUM(tax_total),0)").Where(revenueOrders).Scan(&stats.TotalTax).Error; err != nil {
+		return stats, err
+	}
+	stats.NetRevenue = stats.TotalRevenue - stats.TotalTax
+	if err := db.Table("products").Where("deleted_at IS NULL").Count(&stats.TotalProducts).Error; err != nil {
 		return stats, err
 	}
 	if err := db.Table("reviews").Count(&stats.TotalReviews).Error; err != nil {
@@ -191,7 +226,9 @@ func (r *StatsRepository) GetMonthlyStats() ([]MonthlyStats, error) {
 			SELECT 
 				TO_CHAR(date_trunc('month', created_at), 'YYYY-MM') as month,
 				COUNT(*) as orders,
-				COALESCE(SUM(total), 0) as revenue
+				COALESCE(SUM(total) FILTER (WHERE status NOT IN ('cancelled', 'refunded')), 0) as revenue,
+				COALESCE(SUM(shipping_cost) FILTER (WHERE status NOT IN ('cancelled', 'refunded')), 0) as shipping,
+				COALESCE(SUM(tax_total) FILTER (WHERE status NOT IN ('cancelled', 'refunded')), 0) as tax
 			FROM orders 
 			WHERE created_at >= (SELECT MIN(month_start) FROM months)
 			GROUP BY date_trunc('month', created_at)
@@ -208,6 +245,9 @@ func (r *StatsRepository) GetMonthlyStats() ([]MonthlyStats, error) {
 			m.month,
 			COALESCE(os.orders, 0) as orders,
 			COALESCE(os.revenue, 0) as revenue,
+			COALESCE(os.shipping, 0) as shipping,
+			COALESCE(os.tax, 0) as tax,
+			COALESCE(os.revenue - os.tax, 0) as net_revenue,
 			COALESCE(us.new_users, 0) as new_users
 		FROM months m
 		LEFT JOIN order_stats os ON os.month = m.month
@@ -220,21 +260,33 @@ func (r *StatsRepository) GetMonthlyStats() ([]MonthlyStats, error) {
 	return monthlyStats, nil
 }
 
-func (r *StatsRepository) GetPopularProducts(limit int) ([]ProductStats, error) {
+func (r *StatsRepository) GetPopularProducts(limit int, sortBy string) ([]ProductStats, error) {
 	db := database.DB
 	var popularProducts []ProductStats
 
+	order := "sales_count DESC, view_count DESC"
+	if sortBy == PopularByViews {
+		order = "view_count DESC, sales_count DESC"
+	}
+
 	query := `
-		SELECT 
+		SELECT
+			p.id as product_id,
 			p.name as product_name,
-			COUNT(op.product_id) as sales_count
-		FROM order_products op
-		JOIN products p ON p.id = op.product_id
-		GROUP BY p.id, p.name
-		ORDER BY sales_count DESC
+			COALESCE(s.sales_count, 0) as sales_count,
+			p.view_count as view_count
+		FROM products p
+		LEFT JOIN (
+			SELECT product_id, COUNT(*) as sales_count
+			FROM order_products
+			GROUP BY product_id
+		) s ON s.product_id = p.id
+		WHERE p.deleted_at IS NULL AND p.status = ?
+			AND (s.sales_count > 0 OR p.view_count > 0)
+		ORDER BY ` + order + `
 		LIMIT ?
 	`
-	if err := db.Raw(query, limit).Scan(&popularProducts).Error; err != nil {
+	if err := db.Raw(query, models.ProductStatusActive, limit).Scan(&popularProducts).Error; err != nil {
 		return nil, err
 	}
 	return popularProducts, nil
@@ -248,6 +300,7 @@ func (r *StatsRepository) GetSalesByCategory() ([]CategoryStats, error) {
 		SELECT 
 			p.category as category_name,
 			COALESCE(SUM(op.quantity * op.price), 0) as total_sales,
+			COALESCE(SUM(op.net_amount), 0) as net_sales,
 			COUNT(DISTINCT op.order_id) as order_count
 		FROM order_products op
 		JOIN products p ON p.id = op.product_id
@@ -270,7 +323,7 @@ func (r *StatsRepository) GetChartData() (ChartData, error) {
 	if err != nil {
 		return chartData, err
 	}
-	chartData.PopularProducts, err = r.GetPopularProducts(10)
+	chartData.PopularProducts, err = r.GetPopularProducts(10, PopularBySales)
 	if err != nil {
 		return chartData, err
 	}
@@ -286,7 +339,7 @@ func (r *StatsRepository) GetAdvancedStats() (AdvancedStats, error) {
 	var stats AdvancedStats
 
 	db.Table("orders").Where("status = 'completed'").
-		Select("COALESCE(AVG(total), 0)").Scan(&stats.AverageOrderValue)
+		Select("COALESCE(ROUND(AVG(total)), 0)").Scan(&stats.AverageOrderValue)
 
 	db.Raw(`
 		SELECT COUNT(*) FROM (
@@ -297,7 +350,7 @@ func (r *StatsRepository) GetAdvancedStats() (AdvancedStats, error) {
 	`).Scan(&stats.RepeatCustomers)
 
 	db.Raw(`
-		SELECT COALESCE(AVG(total_orders), 0) FROM (
+		SELECT COALESCE(ROUND(AVG(total_orders)), 0) FROM (
 			SELECT user_id, SUM(total) as total_orders 
 			FROM orders WHERE status = 'completed' 
 			GROUP BY user_id
@@ -410,12 +463,14 @@ func (r *StatsRepository) SaveDailyStats(date time.Time) error {
 	db := database.DB
 
 	var totalUsers, totalOrders, totalProducts int64
-	var totalRevenue float64
+	var totalRevenue, totalShipping, totalTax money.Amount
 
 	db.Model(&models.User{}).Count(&totalUsers)
 	db.Model(&models.Order{}).Count(&totalOrders)
 	db.Model(&models.Product{}).Count(&totalProducts)
-	db.Model(&models.Order{}).Select("COALESCE(SUM(total),0)").Scan(&totalRevenue)
+	db.Model(&models.Order{}).Select("COALESCE(SUM(total),0)").Where(revenueOrders).Scan(&totalRevenue)
+	db.Model(&models.Order{}).Select("COALESCE(SUM(shipping_cost),0)").Where(revenueOrders).Scan(&totalShipping)
+	db.Model(&models.Order{}).Select("COALESCE(SUM(tax_total),0)").Where(revenueOrders).Scan(&totalTax)
 
 	daily := models.DailyStats{
 		Date:          date.Format("2006-01-02"),
@@ -423,7 +478,46 @@ func (r *StatsRepository) SaveDailyStats(date time.Time) error {
 		TotalOrders:   totalOrders,
 		TotalProducts: totalProducts,
 		TotalRevenue:  totalRevenue,
+		TotalShipping: totalShipping,
+		TotalTax:      totalTax,
 	}
 
 	return db.Create(&daily).Error
 }
+
+// Брошенные корзины: корзины пользователей с позициями, не менявшиеся thresholdHours часов.
+func (r *StatsRepository) GetAbandonedCartStats(thresholdHours int) (AbandonedCartStats, error) {
+	db := database.DB
+	stats := AbandonedCartStats{ThresholdHours: thresholdHours}
+	threshold := time.Now().Add(-time.Duration(thresholdHours) * time.Hour)
+
+	err := db.Raw(`
+		SELECT COUNT(DISTINCT c.id) AS abandoned_carts, COALESCE(SUM(ci.price), 0) AS abandoned_value
+		FROM carts c
+		JOIN cart_items ci ON ci.cart_id = c.id
+		WHERE c.user_id IS NOT NULL AND c.updated_at <= ?
+	`, threshold).Scan(&stats).Error
+	if err != nil {
+		return stats, err
+	}
+
+	if err := db.Table("cart_reminders").Count(&stats.RemindersSent).Error; err != nil {
+		return stats, err
+	}
+	if err := db.Table("cart_reminders").Where("recovered_order_id IS NOT NULL").Count(&stats.RecoveredCarts).Error; err != nil {
+		return stats, err
+	}
+	err = db.Raw(`
+		SELECT COALESCE(SUM(o.total), 0)
+		FROM cart_reminders cr
+		JOIN orders o ON o.id = cr.recovered_order_id
+		WHERE o.` + revenueOrders).Scan(&stats.RecoveredValue).Error
+	if err != nil {
+		return stats, err
+	}
+
+	if stats.RemindersSent > 0 {
+		stats.RecoveryRate = float64(stats.RecoveredCarts) / float64(stats.RemindersSent) * 100
+	}
+	return stats, nil
+}
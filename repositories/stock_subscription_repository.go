@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"time"
+
+	"1kosmetika-marketplace-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StockSubscriptionRepository interface {
+	// Upsert создаёт подписку или снова ставит в очередь уже уведомлённую.
+	Upsert(sub *models.StockSubscription) error
+	DeleteByUser(productID, userID uint) (bool, error)
+	// FindPending — неотправленные подписки на товары, которые уже в наличии,
+	// в порядке подписки.
+	FindPending(limit int) ([]models.StockSubscription, error)
+	MarkNotified(id uint, at time.Time) error
+}
+
+type stockSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewStockSubscriptionRepository(db *gorm.DB) StockSubscriptionRepository {
+	return &stockSubscriptionRepository{db: db}
+}
+
+func (r *stockSubscriptionRepository) Upsert(sub *models.StockSubscription) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "product_id"}, {Name: "email"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"user_id":     gorm.Expr("COALESCE(EXCLUDED.user_id, stock_subscriptions.user_id)"),
+			"notified_at": nil,
+			"updated_at":  gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).Create(sub).Error
+}
+
+func (r *stockSubscriptionRepository) DeleteByUser(productID, userID uint) (bool, error) {
+	result := r.db.Where("product_id = ? AND user_id = ? AND notified_at IS NULL", productID, userID).
+		Delete(&models.StockSubscription{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *stockSubscriptionRepository) FindPending(limit int) ([]models.StockSubscription, error) {
+	var subs []models.StockSubscription
+	err := r.db.
+		Joins("JOIN products ON products.id = stock_subscriptions.product_id").
+		Where("stock_subscriptions.notified_at IS NULL").
+		Where("products.stock > 0 AND products.status = ? AND products.deleted_at IS NULL", models.ProductStatusActive).
+		Order("stock_subscriptions.created_at, stock_subscriptions.id").
+		Limit(limit).
+		Find(&subs).Error
+	return subs, err
+}
+
+func (r *stockSubscriptionRepository) MarkNotified(id uint, at time.Time) error {
+	return r.db.Model(&models.StockSubscription{}).Where("id = ?", id).Update("notified_at", at).Error
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"1kosmetika-marketplace-backend/models"
+
+	"gorm.io/gorm"
+)
+
+type TaxRepository interface {
+	Create(rate *models.TaxRate) error
+	Update(rate *models.TaxRate) error
+	Delete(id uint) error
+	FindByID(id uint) (*models.TaxRate, error)
+	FindAll() ([]models.TaxRate, error)
+	FindActive() ([]models.TaxRate, error)
+}
+
+type taxRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxRepository(db *gorm.DB) TaxRepository {
+	return &taxRepository{db: db}
+}
+
+func (r *taxRepository) Create(rate *models.TaxRate) error {
+	return r.db.Create(rate).Error
+}
+
+func (r *taxRepository) Update(rate *models.TaxRate) error {
+	return r.db.Save(rate).Error
+}
+
+func (r *taxRepository) Delete(id uint) error {
+	return r.db.Delete(&models.TaxRate{}, id).Error
+}
+
+func (r *taxRepository) FindByID(id uint) (*models.TaxRate, error) {
+	var rate models.TaxRate
+	err := r.db.First(&rate, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *taxRepository) FindAll() ([]models.TaxRate, error) {
+	var rates []models.TaxRate
+	err := r.db.Order("id").Find(&rates).Error
+	return rates, err
+}
+
+func (r *taxRepository) FindActive() ([]models.TaxRate, error) {
+	var rates []models.TaxRate
+	err := r.db.Where("is_active = ?", true).Find(&rates).Error
+	return rates, err
+}
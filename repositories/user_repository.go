@@ -14,6 +14,7 @@ type UserRepository interface {
 	Delete(id uint) error
 	GetAll() ([]models.User, error)
 	UpdateRole(id uint, role string) error
+	FindByRole(role string) ([]models.User, error)
 }
 
 type userRepository struct {
@@ -56,4 +57,10 @@ func (r *userRepository) GetAll() ([]models.User, error) {
 
 func (r *userRepository) UpdateRole(id uint, role string) error {
 	return r.db.Model(&models.User{}).Where("id = ?", id).Update("role", role).Error
-}
\ No newline at end of file
+}
+
+func (r *userRepository) FindByRole(role string) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("role = ?", role).Find(&users).Error
+	return users, err
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"1kosmetika-marketplace-backend/models"
+
+	"gorm.io/gorm"
+)
+
+type WishlistRepository interface {
+	Create(wishlist *models.Wishlist) error
+	Update(wishlist *models.Wishlist) error
+	Delete(id uint) error
+	FindByID(id uint) (*models.Wishlist, error)
+	FindByUserID(userID uint) ([]models.Wishlist, error)
+	FindByShareToken(token string) (*models.Wishlist, error)
+	FindOrCreateDefault(userID uint) (*models.Wishlist, error)
+}
+
+type wishlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWishlistRepository(db *gorm.DB) WishlistRepository {
+	return &wishlistRepository{db: db}
+}
+
+// Удалённые товары в списках не показываются, как и в избранном.
+func preloadWishlistItems(db *gorm.DB) *gorm.DB {
+	return db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Where("product_id IN (SELECT id FROM products WHERE deleted_at IS NULL)").Order("created_at DESC")
+	}).Preload("Items.Product")
+}
+
+func (r *wishlistRepository) Create(wishlist *models.Wishlist) error {
+	return r.db.Omit("Items").Create(wishlist).Error
+}
+
+func (r *wishlistRepository) Update(wishlist *models.Wishlist) error {
+	return r.db.Omit("Items").Save(wishlist).Error
+}
+
+func (r *wishlistRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("wishlist_id = ?", id).Delete(&models.Favorite{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Wishlist{}, id).Error
+	})
+}
+
+func (r *wishlistRepository) FindByID(id uint) (*models.Wishlist, error) {
+	var wishlist models.Wishlist
+	err := preloadWishlistItems(r.db).First(&wishlist, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &wishlist, nil
+}
+
+func (r *wishlistRepository) FindByUserID(userID uint) ([]models.Wishlist, error) {
+	var wishlists []models.Wishlist
+	err := preloadWishlistItems(r.db).Where("user_id = ?", userID).
+		Order("is_default DESC, created_at").Find(&wishlists).Error
+	return wishlists, err
+}
+
+func (r *wishlistRepository) FindByShareToken(token string) (*models.Wishlist, error) {
+	var wishlist models.Wishlist
+	err := preloadWishlistItems(r.db).Where("share_token = ?", token).First(&wishlist).Error
+	if err != nil {
+		return nil, err
+	}
+	return &wishlist, nil
+}
+
+func (r *wishlistRepository) FindOrCreateDefault(userID uint) (*models.Wishlist, error) {
+	var wishlist models.Wishlist
+	err := r.db.Where(models.Wishlist{UserID: userID, IsDefault: true}).
+		Attrs(models.Wishlist{Name: models.DefaultWishlistName}).
+		FirstOrCreate(&wishlist).Error
+	if err != nil {
+		return nil, err
+	}
+	return &wishlist, nil
+}
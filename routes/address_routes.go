@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"1kosmetika-marketplace-backend/handlers"
+	"1kosmetika-marketplace-backend/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAddressRoutes(r *gin.Engine, addressHandler *handlers.AddressHandler) {
+	r.GET("/api/regions", addressHandler.GetRegions)
+
+	addresses := r.Group("/api/addresses")
+	addresses.Use(middlewares.JWTAuth())
+	{
+		addresses.GET("/", addressHandler.GetAddresses)
+		addresses.POST("/", addressHandler.CreateAddress)
+		addresses.PUT("/:id", addressHandler.UpdateAddress)
+		addresses.DELETE("/:id", addressHandler.DeleteAddress)
+		addresses.PUT("/:id/default", addressHandler.SetDefaultAddress)
+	}
+}
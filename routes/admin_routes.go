@@ -27,5 +27,6 @@ func SetupAdminRoutes(r *gin.Engine, statsHandler *handlers.StatsHandler) {
 	admin.GET("/stats/conversion", statsHandler.GetConversionStats)
 	admin.GET("/stats/refunds", statsHandler.GetRefundStats)
 	admin.GET("/stats/profit", statsHandler.GetProfitStats)
+	admin.GET("/stats/abandoned-carts", statsHandler.GetAbandonedCartStats)
 
 }
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"1kosmetika-marketplace-backend/handlers"
+	"1kosmetika-marketplace-backend/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupBeautyProfileRoutes(r *gin.Engine, beautyProfileHandler *handlers.BeautyProfileHandler) {
+	r.GET("/api/beauty-profile/options", beautyProfileHandler.GetOptions)
+
+	me := r.Group("/api/users/me")
+	me.Use(middlewares.JWTAuth())
+	{
+		me.GET("/beauty-profile", beautyProfileHandler.GetProfile)
+		me.PUT("/beauty-profile", beautyProfileHandler.SaveProfile)
+		me.DELETE("/beauty-profile", beautyProfileHandler.DeleteProfile)
+	}
+}
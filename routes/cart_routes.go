@@ -12,8 +12,22 @@ func SetupCartRoutes(r *gin.Engine, cartHandler *handlers.CartHandler) {
 	cart.Use(middlewares.JWTAuth())
 	{
 		cart.GET("/", cartHandler.GetCart)
+		cart.PUT("/", cartHandler.SetCart)
+		cart.POST("/merge", cartHandler.MergeCart)
 		cart.POST("/items", cartHandler.AddToCart)
+		cart.PATCH("/items/:id", cartHandler.UpdateCartItem)
+		cart.POST("/items/:id/move-to-favorites", cartHandler.MoveToFavorites)
 		cart.DELETE("/items/:id", cartHandler.RemoveFromCart)
 		cart.DELETE("/clear", cartHandler.ClearCart)
 	}
-}
\ No newline at end of file
+
+	guestCart := r.Group("/api/guest/cart")
+	guestCart.Use(middlewares.GuestSession())
+	{
+		guestCart.GET("/", cartHandler.GetGuestCart)
+		guestCart.POST("/items", cartHandler.AddToGuestCart)
+		guestCart.PATCH("/items/:id", cartHandler.UpdateGuestCartItem)
+		guestCart.DELETE("/items/:id", cartHandler.RemoveFromGuestCart)
+		guestCart.DELETE("/clear", cartHandler.ClearGuestCart)
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"1kosmetika-marketplace-backend/handlers"
+	"1kosmetika-marketplace-backend/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupCurrencyRoutes(r *gin.Engine, currencyHandler *handlers.CurrencyHandler) {
+	r.GET("/api/currencies", currencyHandler.GetCurrencies)
+
+	rates := r.Group("/api/admin/exchange-rates")
+	rates.Use(middlewares.JWTAuth(), middlewares.AdminOnly())
+	{
+		rates.GET("/", currencyHandler.GetExchangeRates)
+		rates.PUT("/:currency", currencyHandler.SetExchangeRate)
+		rates.DELETE("/:currency", currencyHandler.DeleteExchangeRate)
+	}
+}
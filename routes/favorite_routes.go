@@ -15,5 +15,6 @@ func SetupFavoriteRoutes(r *gin.Engine, favoriteHandler *handlers.FavoriteHandle
 		favorites.POST("/:productId", favoriteHandler.AddFavorite)
 		favorites.DELETE("/:productId", favoriteHandler.RemoveFavorite)
 		favorites.GET("/check/:productId", favoriteHandler.CheckFavorite)
+		favorites.PUT("/:productId/alerts", favoriteHandler.SetAlerts)
 	}
 }
\ No newline at end of file
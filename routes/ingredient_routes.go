@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"1kosmetika-marketplace-backend/handlers"
+	"1kosmetika-marketplace-backend/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupIngredientRoutes(r *gin.Engine, ingredientHandler *handlers.IngredientHandler) {
+	r.GET("/api/ingredients", ingredientHandler.GetIngredients)
+	r.GET("/api/ingredients/:id", ingredientHandler.GetIngredient)
+	r.GET("/api/products/:id/ingredients", ingredientHandler.GetProductIngredients)
+	r.PUT("/api/products/:id/ingredients", middlewares.JWTAuth(), middlewares.AdminOnly(), ingredientHandler.SetProductIngredients)
+
+	admin := r.Group("/api/admin/ingredients")
+	admin.Use(middlewares.JWTAuth(), middlewares.AdminOnly())
+	{
+		admin.POST("/", ingredientHandler.CreateIngredient)
+		admin.POST("/import", ingredientHandler.ImportIngredients)
+		admin.PUT("/:id", ingredientHandler.UpdateIngredient)
+		admin.DELETE("/:id", ingredientHandler.DeleteIngredient)
+	}
+}
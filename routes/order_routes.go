@@ -14,8 +14,16 @@ func SetupOrderRoutes(r *gin.Engine, orderHandler *handlers.OrderHandler) {
 		orders.POST("/", orderHandler.CreateOrder)
 		orders.GET("/", orderHandler.GetUserOrders)
 		orders.GET("/:id", orderHandler.GetOrderByID)
+		orders.POST("/:id/cancel", orderHandler.CancelOrder)
+		orders.GET("/:id/invoice", orderHandler.GetInvoice)
 		
 	
 		orders.GET("/admin/all", middlewares.AdminOnly(), orderHandler.GetAllOrders)
 	}
-}
\ No newline at end of file
+
+	guest := r.Group("/api/guest")
+	guest.Use(middlewares.GuestSession())
+	{
+		guest.POST("/checkout", orderHandler.GuestCheckout)
+	}
+}
@@ -11,9 +11,11 @@ func SetupProductRoutes(r *gin.Engine, productHandler *handlers.ProductHandler)
 	products := r.Group("/api/products")
 	{
 		products.GET("/", productHandler.GetProducts)
-		products.GET("/:id", productHandler.GetProductByID)
+		products.GET("/:id", middlewares.SoftJWTAuth(), productHandler.GetProductByID)
+		products.GET("/slug/:slug", middlewares.SoftJWTAuth(), productHandler.GetProductBySlug)
+		products.GET("/:id/price-history", productHandler.GetPriceHistory)
 		products.GET("/paginated", productHandler.GetProductsPaginated)
-		products.GET("/search", productHandler.SearchProducts)
+		products.GET("/search", middlewares.SoftJWTAuth(), productHandler.SearchProducts)
 		products.GET("/categories", productHandler.GetCategories)
 		products.GET("/brands", productHandler.GetBrands)
 
@@ -22,8 +24,11 @@ func SetupProductRoutes(r *gin.Engine, productHandler *handlers.ProductHandler)
 		adminRoutes := products.Group("")
 		adminRoutes.Use(middlewares.JWTAuth(), middlewares.AdminOnly())
 		{
+			adminRoutes.GET("/admin/all", productHandler.GetAdminProducts)
+			adminRoutes.GET("/admin/:id", productHandler.GetAdminProductByID)
 			adminRoutes.POST("/", productHandler.CreateProduct)
 			adminRoutes.PUT("/:id", productHandler.UpdateProduct)
+			adminRoutes.POST("/:id/restock", productHandler.RestockProduct)
 			adminRoutes.DELETE("/:id", productHandler.DeleteProduct)
 		}
 	}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"1kosmetika-marketplace-backend/handlers"
+	"1kosmetika-marketplace-backend/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupQuestionRoutes(r *gin.Engine, questionHandler *handlers.QuestionHandler) {
+	r.GET("/api/products/:id/questions", questionHandler.GetProductQuestions)
+	r.POST("/api/products/:id/questions", middlewares.JWTAuth(), questionHandler.AskQuestion)
+	r.POST("/api/questions/:id/answers", middlewares.JWTAuth(), questionHandler.AnswerQuestion)
+	r.POST("/api/answers/:id/upvote", middlewares.JWTAuth(), questionHandler.UpvoteAnswer)
+	r.DELETE("/api/answers/:id/upvote", middlewares.JWTAuth(), questionHandler.RemoveUpvote)
+
+	moderation := r.Group("/api/admin/questions")
+	moderation.Use(middlewares.JWTAuth(), middlewares.AdminOnly())
+	{
+		moderation.GET("/", questionHandler.GetModerationQueue)
+		moderation.PUT("/:id/moderate", questionHandler.ModerateQuestion)
+		moderation.DELETE("/:id", questionHandler.DeleteQuestion)
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"1kosmetika-marketplace-backend/handlers"
+	"1kosmetika-marketplace-backend/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRecentViewRoutes(r *gin.Engine, recentViewHandler *handlers.RecentViewHandler) {
+	me := r.Group("/api/users/me")
+	me.Use(middlewares.JWTAuth())
+	{
+		me.GET("/recently-viewed", recentViewHandler.GetRecentlyViewed)
+		me.DELETE("/recently-viewed", recentViewHandler.ClearRecentlyViewed)
+	}
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"1kosmetika-marketplace-backend/handlers"
+	"1kosmetika-marketplace-backend/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRecommendationRoutes(r *gin.Engine, recommendationHandler *handlers.RecommendationHandler) {
+	r.GET("/api/products/:id/related", recommendationHandler.GetRelatedProducts)
+	r.GET("/api/recommendations", middlewares.JWTAuth(), recommendationHandler.GetRecommendations)
+}
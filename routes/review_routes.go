@@ -13,7 +13,21 @@ func SetupReviewRoutes(r *gin.Engine, reviewHandler *handlers.ReviewHandler) {
 		reviews.GET("/product/:productId", reviewHandler.GetProductReviews)
 		reviews.GET("/user", middlewares.JWTAuth(), reviewHandler.GetUserReviews)
 		reviews.POST("/", middlewares.JWTAuth(), reviewHandler.CreateReview)
+		reviews.POST("/photos", middlewares.JWTAuth(), handlers.UploadReviewPhotoHandler)
 		reviews.PUT("/:id", middlewares.JWTAuth(), reviewHandler.UpdateReview)
 		reviews.DELETE("/:id", middlewares.JWTAuth(), reviewHandler.DeleteReview)
+		reviews.POST("/:id/vote", middlewares.JWTAuth(), reviewHandler.VoteReview)
+		reviews.DELETE("/:id/vote", middlewares.JWTAuth(), reviewHandler.RemoveVote)
+		reviews.POST("/:id/report", middlewares.JWTAuth(), reviewHandler.ReportReview)
+	}
+
+	moderation := r.Group("/api/admin/reviews")
+	moderation.Use(middlewares.JWTAuth(), middlewares.AdminOnly())
+	{
+		moderation.GET("/", reviewHandler.GetModerationQueue)
+		moderation.PUT("/:id/moderate", reviewHandler.ModerateReview)
+		moderation.GET("/:id/reports", reviewHandler.GetReviewReports)
+		moderation.POST("/:id/replies", reviewHandler.ReplyToReview)
+		moderation.DELETE("/:id/replies/:replyId", reviewHandler.DeleteReply)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"1kosmetika-marketplace-backend/handlers"
+	"1kosmetika-marketplace-backend/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupShipmentRoutes(r *gin.Engine, shipmentHandler *handlers.ShipmentHandler) {
+	r.GET("/api/orders/:id/tracking", middlewares.JWTAuth(), shipmentHandler.GetOrderTracking)
+
+	admin := r.Group("/api/admin")
+	admin.Use(middlewares.JWTAuth(), middlewares.AdminOnly())
+	{
+		admin.POST("/orders/:id/shipments", shipmentHandler.CreateShipment)
+		admin.POST("/shipments/:id/events", shipmentHandler.AddShipmentEvent)
+	}
+
+	r.POST("/api/webhooks/carriers/:carrier", shipmentHandler.CarrierWebhook)
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"1kosmetika-marketplace-backend/handlers"
+	"1kosmetika-marketplace-backend/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupShippingRoutes(r *gin.Engine, shippingHandler *handlers.ShippingHandler) {
+	r.GET("/api/shipping/methods", shippingHandler.GetShippingOptions)
+
+	admin := r.Group("/api/admin/shipping-methods")
+	admin.Use(middlewares.JWTAuth(), middlewares.AdminOnly())
+	{
+		admin.GET("/", shippingHandler.GetAllMethods)
+		admin.POST("/", shippingHandler.CreateMethod)
+		admin.PUT("/:id", shippingHandler.UpdateMethod)
+		admin.DELETE("/:id", shippingHandler.DeleteMethod)
+		admin.POST("/:id/rates", shippingHandler.AddRate)
+		admin.PUT("/:id/rates/:rateId", shippingHandler.UpdateRate)
+		admin.DELETE("/:id/rates/:rateId", shippingHandler.DeleteRate)
+	}
+}
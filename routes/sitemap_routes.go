@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"1kosmetika-marketplace-backend/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupSitemapRoutes(r *gin.Engine, sitemapHandler *handlers.SitemapHandler) {
+	r.GET("/sitemap.xml", sitemapHandler.GetSitemap)
+}
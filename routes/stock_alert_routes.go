@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"1kosmetika-marketplace-backend/handlers"
+	"1kosmetika-marketplace-backend/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupStockAlertRoutes(r *gin.Engine, stockAlertHandler *handlers.StockAlertHandler) {
+	r.POST("/api/products/:id/notify-me", middlewares.OptionalJWTAuth(), stockAlertHandler.NotifyMe)
+	r.DELETE("/api/products/:id/notify-me", middlewares.JWTAuth(), stockAlertHandler.CancelNotifyMe)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"1kosmetika-marketplace-backend/handlers"
+	"1kosmetika-marketplace-backend/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTaxRoutes(r *gin.Engine, taxHandler *handlers.TaxHandler) {
+	taxes := r.Group("/api/admin/tax-rates")
+	taxes.Use(middlewares.JWTAuth(), middlewares.AdminOnly())
+	{
+		taxes.GET("/", taxHandler.GetTaxRates)
+		taxes.POST("/", taxHandler.CreateTaxRate)
+		taxes.PUT("/:id", taxHandler.UpdateTaxRate)
+		taxes.DELETE("/:id", taxHandler.DeleteTaxRate)
+	}
+}
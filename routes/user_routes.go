@@ -13,6 +13,7 @@ func SetupUserRoutes(r *gin.Engine, userHandler *handlers.UserHandler) {
 		auth.POST("/register", userHandler.Register)
 		auth.POST("/login", userHandler.Login)
 		auth.GET("/profile", middlewares.JWTAuth(), userHandler.GetProfile)
+		auth.PUT("/preferences", middlewares.JWTAuth(), userHandler.UpdatePreferences)
 	}
 
 	admin := r.Group("/api/admin")
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"1kosmetika-marketplace-backend/handlers"
+	"1kosmetika-marketplace-backend/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupWishlistRoutes(r *gin.Engine, wishlistHandler *handlers.WishlistHandler) {
+	r.GET("/api/wishlists/shared/:token", wishlistHandler.GetSharedWishlist)
+
+	wishlists := r.Group("/api/wishlists")
+	wishlists.Use(middlewares.JWTAuth())
+	{
+		wishlists.GET("/", wishlistHandler.GetWishlists)
+		wishlists.POST("/", wishlistHandler.CreateWishlist)
+		wishlists.GET("/:id", wishlistHandler.GetWishlist)
+		wishlists.PUT("/:id", wishlistHandler.RenameWishlist)
+		wishlists.DELETE("/:id", wishlistHandler.DeleteWishlist)
+		wishlists.POST("/:id/items/:productId", wishlistHandler.AddItem)
+		wishlists.DELETE("/:id/items/:productId", wishlistHandler.RemoveItem)
+		wishlists.POST("/:id/share", wishlistHandler.ShareWishlist)
+		wishlists.DELETE("/:id/share", wishlistHandler.UnshareWishlist)
+	}
+}
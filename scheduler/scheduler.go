@@ -6,10 +6,21 @@ import (
 
 	"github.com/robfig/cron/v3"
 	"1kosmetika-marketplace-backend/repositories"
+	"1kosmetika-marketplace-backend/services"
 )
 
+// Dependencies — сервисы, которые нужны фоновым задачам.
+type Dependencies struct {
+	ProductService        services.ProductService
+	AbandonedCartService  services.AbandonedCartService
+	ShipmentService       services.ShipmentService
+	FavoriteService       services.FavoriteService
+	StockAlertService     services.StockAlertService
+	ReviewService         services.ReviewService
+	RecommendationService services.RecommendationService
+}
 
-func StartCronJobs() {
+func StartCronJobs(deps Dependencies) {
 	c := cron.New()
 
 
@@ -23,6 +34,10 @@ func StartCronJobs() {
 		} else {
 			log.Println("✅ Daily stats saved successfully")
 		}
+
+		if err := deps.ReviewService.RepairRatings(); err != nil {
+			log.Println("❌ Failed to repair product ratings:", err)
+		}
 	})
 	if err != nil {
 		log.Println("❌ Failed to schedule daily stats job:", err)
@@ -31,11 +46,60 @@ func StartCronJobs() {
 
 	_, err = c.AddFunc("@hourly", func() {
 		log.Println("♻️ Hourly cache refresh job running...")
+
+		if err := deps.AbandonedCartService.SendReminders(time.Now()); err != nil {
+			log.Println("❌ Failed to send abandoned cart reminders:", err)
+		}
+		if err := deps.AbandonedCartService.CleanupGuestCarts(time.Now()); err != nil {
+			log.Println("❌ Failed to clean up guest carts:", err)
+		}
 	})
 	if err != nil {
 		log.Println("❌ Failed to schedule hourly job:", err)
 	}
 
+	_, err = c.AddFunc("@every 1m", func() {
+		if err := deps.ProductService.PublishScheduledProducts(time.Now()); err != nil {
+			log.Println("❌ Failed to publish scheduled products:", err)
+		}
+		if err := deps.ProductService.ApplyScheduledPrices(time.Now()); err != nil {
+			log.Println("❌ Failed to apply scheduled prices:", err)
+		}
+		if err := deps.StockAlertService.ProcessPending(time.Now()); err != nil {
+			log.Println("❌ Failed to send back-in-stock notifications:", err)
+		}
+	})
+	if err != nil {
+		log.Println("❌ Failed to schedule product publishing/pricing job:", err)
+	}
+
+	_, err = c.AddFunc("@every 15m", func() {
+		if err := deps.ShipmentService.PollShipments(time.Now()); err != nil {
+			log.Println("❌ Failed to poll shipment tracking:", err)
+		}
+	})
+	if err != nil {
+		log.Println("❌ Failed to schedule shipment tracking job:", err)
+	}
+
+	_, err = c.AddFunc("@every 10m", func() {
+		if err := deps.FavoriteService.SendAlerts(); err != nil {
+			log.Println("❌ Failed to send favorite alerts:", err)
+		}
+	})
+	if err != nil {
+		log.Println("❌ Failed to schedule favorite alerts job:", err)
+	}
+
+	_, err = c.AddFunc("0 3 * * *", func() {
+		if err := deps.RecommendationService.Rebuild(); err != nil {
+			log.Println("❌ Failed to rebuild recommendations:", err)
+		}
+	})
+	if err != nil {
+		log.Println("❌ Failed to schedule recommendations job:", err)
+	}
+
 	c.Start()
 	log.Println("🚀 Cron scheduler started")
 }
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"time"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
+	"1kosmetika-marketplace-backend/repositories"
+	"1kosmetika-marketplace-backend/utils"
+)
+
+const (
+	// Корзины старше этого срока считаются окончательно брошенными — напоминать поздно.
+	abandonedCartMaxAge = 30 * 24 * time.Hour
+	// Заказ в этом окне после напоминания засчитывается как восстановление.
+	cartRecoveryWindow = 7 * 24 * time.Hour
+)
+
+type AbandonedCartService interface {
+	SendReminders(now time.Time) error
+	CleanupGuestCarts(now time.Time) error
+}
+
+type abandonedCartService struct {
+	cartRepo         repositories.CartRepository
+	reminderRepo     repositories.CartReminderRepository
+	userRepo         repositories.UserRepository
+	notificationRepo repositories.NotificationRepository
+	abandonAfter     time.Duration
+}
+
+func NewAbandonedCartService(
+	cartRepo repositories.CartRepository,
+	reminderRepo repositories.CartReminderRepository,
+	userRepo repositories.UserRepository,
+	notificationRepo repositories.NotificationRepository,
+	abandonAfter time.Duration,
+) AbandonedCartService {
+	return &abandonedCartService{
+		cartRepo:         cartRepo,
+		reminderRepo:     reminderRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		abandonAfter:     abandonAfter,
+	}
+}
+
+func (s *abandonedCartService) SendReminders(now time.Time) error {
+	carts, err := s.cartRepo.FindAbandoned(now.Add(-s.abandonAfter), now.Add(-abandonedCartMaxAge))
+	if err != nil {
+		return fmt.Errorf("failed to find abandoned carts: %w", err)
+	}
+
+	sent := 0
+	for i := range carts {
+		cart := &carts[i]
+		if cart.UserID == nil {
+			continue
+		}
+
+		alreadySent, err := s.reminderRepo.ExistsForCartVersion(cart.ID, cart.UpdatedAt)
+		if err != nil || alreadySent {
+			continue
+		}
+
+		value, names := inStockValue(cart)
+		if value == 0 {
+			continue
+		}
+
+		user, err := s.userRepo.FindByID(*cart.UserID)
+		if err != nil || user.CartRemindersOptOut {
+			continue
+		}
+
+		_ = s.notificationRepo.Create(&models.Notification{
+			UserID:  user.ID,
+			Title:   "Вы забыли товары в корзине",
+			Message: fmt.Sprintf("В вашей корзине ждут товары на сумму %s. Оформите заказ, пока они в наличии.", money.Format(value)),
+			Type:    "info",
+		})
+
+		body := fmt.Sprintf(`
+	<h2>Здравствуйте, %s!</h2>
+	<p>В вашей корзине остались товары: %s.</p>
+	<p>Сумма: <b>%s</b></p>
+`, html.EscapeString(user.FullName), html.EscapeString(names), money.Format(value))
+		if err := utils.SendEmail(user.Email, "Товары ждут вас в корзине", body); err != nil {
+			log.Printf("❌ Failed to send cart reminder email to user %d: %v", user.ID, err)
+		}
+
+		if err := s.reminderRepo.Create(&models.CartReminder{
+			CartID:        cart.ID,
+			UserID:        user.ID,
+			CartUpdatedAt: cart.UpdatedAt,
+			CartValue:     value,
+			SentAt:        now,
+		}); err != nil {
+			log.Printf("❌ Failed to record cart reminder for cart %d: %v", cart.ID, err)
+			continue
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		log.Printf("🛒 Abandoned cart reminders sent: %d", sent)
+	}
+	return nil
+}
+
+func (s *abandonedCartService) CleanupGuestCarts(now time.Time) error {
+	deleted, err := s.cartRepo.DeleteStaleGuestCarts(now.Add(-abandonedCartMaxAge))
+	if err != nil {
+		return fmt.Errorf("failed to delete stale guest carts: %w", err)
+	}
+	if deleted > 0 {
+		log.Printf("🗑️ Deleted %d stale guest carts", deleted)
+	}
+	return nil
+}
+
+// Сумма и названия позиций, которые всё ещё можно купить в нужном количестве.
+func inStockValue(cart *models.Cart) (money.Amount, string) {
+	var value money.Amount
+	names := ""
+	for _, item := range cart.Items {
+		p := item.Product
+		if p.ID == 0 || p.Status != models.ProductStatusActive || p.Stock < item.Quantity {
+			continue
+		}
+		value += p.Price.Mul(item.Quantity)
+		if names != "" {
+			names += ", "
+		}
+		names += p.Name
+	}
+	return value, names
+}
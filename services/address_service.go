@@ -0,0 +1,132 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/repositories"
+)
+
+type AddressService interface {
+	GetAddresses(userID uint) ([]models.Address, error)
+	CreateAddress(userID uint, address *models.Address) error
+	UpdateAddress(userID, addressID uint, input *models.Address) (*models.Address, error)
+	DeleteAddress(userID, addressID uint) error
+	SetDefaultAddress(userID, addressID uint) error
+}
+
+type addressService struct {
+	addressRepo repositories.AddressRepository
+}
+
+func NewAddressService(addressRepo repositories.AddressRepository) AddressService {
+	return &addressService{addressRepo: addressRepo}
+}
+
+func (s *addressService) GetAddresses(userID uint) ([]models.Address, error) {
+	return s.addressRepo.FindByUserID(userID)
+}
+
+func (s *addressService) CreateAddress(userID uint, address *models.Address) error {
+	region, err := normalizeRegion(address.Region)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.addressRepo.FindByUserID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get addresses: %w", err)
+	}
+
+	address.ID = 0
+	address.UserID = userID
+	address.Region = region
+	// Первый адрес автоматически становится адресом по умолчанию.
+	makeDefault := address.IsDefault || len(existing) == 0
+	address.IsDefault = false
+
+	if err := s.addressRepo.Create(address); err != nil {
+		return fmt.Errorf("failed to create address: %w", err)
+	}
+	if makeDefault {
+		if err := s.addressRepo.SetDefault(userID, address.ID); err != nil {
+			return fmt.Errorf("failed to set default address: %w", err)
+		}
+		address.IsDefault = true
+	}
+	return nil
+}
+
+func (s *addressService) UpdateAddress(userID, addressID uint, input *models.Address) (*models.Address, error) {
+	address, err := s.findOwned(userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+	region, err := normalizeRegion(input.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	address.FullName = input.FullName
+	address.Phone = input.Phone
+	address.Region = region
+	address.City = input.City
+	address.AddressLine = input.AddressLine
+	address.PostalCode = input.PostalCode
+
+	if err := s.addressRepo.Update(address); err != nil {
+		return nil, fmt.Errorf("failed to update address: %w", err)
+	}
+	if input.IsDefault && !address.IsDefault {
+		if err := s.addressRepo.SetDefault(userID, address.ID); err != nil {
+			return nil, fmt.Errorf("failed to set default address: %w", err)
+		}
+		address.IsDefault = true
+	}
+	return address, nil
+}
+
+func (s *addressService) DeleteAddress(userID, addressID uint) error {
+	address, err := s.findOwned(userID, addressID)
+	if err != nil {
+		return err
+	}
+	if err := s.addressRepo.Delete(userID, addressID); err != nil {
+		return fmt.Errorf("failed to delete address: %w", err)
+	}
+
+	// Если удалён адрес по умолчанию, им становится самый свежий из оставшихся.
+	if address.IsDefault {
+		rest, err := s.addressRepo.FindByUserID(userID)
+		if err == nil && len(rest) > 0 {
+			_ = s.addressRepo.SetDefault(userID, rest[0].ID)
+		}
+	}
+	return nil
+}
+
+func (s *addressService) SetDefaultAddress(userID, addressID uint) error {
+	if _, err := s.findOwned(userID, addressID); err != nil {
+		return err
+	}
+	return s.addressRepo.SetDefault(userID, addressID)
+}
+
+func (s *addressService) findOwned(userID, addressID uint) (*models.Address, error) {
+	address, err := s.addressRepo.FindByID(addressID)
+	if err != nil || address.UserID != userID {
+		return nil, fmt.Errorf("address not found")
+	}
+	return address, nil
+}
+
+func normalizeRegion(region string) (string, error) {
+	region = strings.ToLower(strings.TrimSpace(region))
+	for _, r := range models.Regions {
+		if r == region {
+			return region, nil
+		}
+	}
+	return "", fmt.Errorf("unknown region %q, expected one of: %s", region, strings.Join(models.Regions, ", "))
+}
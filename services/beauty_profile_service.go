@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/repositories"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxProfileAllergies = 30
+	maxAllergenLength   = 100
+)
+
+// BeautyProfileInput — данные профиля от пользователя; списки заменяются целиком.
+type BeautyProfileInput struct {
+	SkinType  string   `json:"skin_type"`
+	SkinTone  string   `json:"skin_tone"`
+	HairType  string   `json:"hair_type"`
+	Concerns  []string `json:"concerns"`
+	Allergies []string `json:"allergies"`
+}
+
+type BeautyProfileService interface {
+	// GetProfile возвращает nil без ошибки, если профиль не заполнен.
+	GetProfile(userID uint) (*models.BeautyProfile, error)
+	SaveProfile(userID uint, input BeautyProfileInput) (*models.BeautyProfile, error)
+	DeleteProfile(userID uint) error
+}
+
+type beautyProfileService struct {
+	profileRepo repositories.BeautyProfileRepository
+}
+
+func NewBeautyProfileService(profileRepo repositories.BeautyProfileRepository) BeautyProfileService {
+	return &beautyProfileService{profileRepo: profileRepo}
+}
+
+func (s *beautyProfileService) GetProfile(userID uint) (*models.BeautyProfile, error) {
+	profile, err := s.profileRepo.FindByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return profile, err
+}
+
+func (s *beautyProfileService) SaveProfile(userID uint, input BeautyProfileInput) (*models.BeautyProfile, error) {
+	if err := validateBeautyValue("skin_type", input.SkinType, models.SkinTypes); err != nil {
+		return nil, err
+	}
+	if err := validateBeautyValue("skin_tone", input.SkinTone, models.SkinTones); err != nil {
+		return nil, err
+	}
+	if err := validateBeautyValue("hair_type", input.HairType, models.HairTypes); err != nil {
+		return nil, err
+	}
+	concerns, err := normalizeBeautyList("concerns", input.Concerns, models.BeautyConcerns)
+	if err != nil {
+		return nil, err
+	}
+	allergies, err := normalizeAllergies(input.Allergies)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &models.BeautyProfile{
+		UserID:    userID,
+		SkinType:  input.SkinType,
+		SkinTone:  input.SkinTone,
+		HairType:  input.HairType,
+		Concerns:  concerns,
+		Allergies: allergies,
+	}
+	if err := s.profileRepo.Upsert(profile); err != nil {
+		return nil, fmt.Errorf("failed to save beauty profile: %w", err)
+	}
+	return s.profileRepo.FindByUserID(userID)
+}
+
+func (s *beautyProfileService) DeleteProfile(userID uint) error {
+	deleted, err := s.profileRepo.DeleteByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("beauty profile not found")
+	}
+	return nil
+}
+
+// validateBeautyValue допускает пустое значение — «не указано».
+func validateBeautyValue(field, value string, allowed []string) error {
+	if value == "" {
+		return nil
+	}
+	for _, v := range allowed {
+		if v == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s must be one of: %s", field, strings.Join(allowed, ", "))
+}
+
+// normalizeBeautyList проверяет значения и убирает повторы; nil превращается
+// в пустой список, чтобы в базе не оказался JSON null.
+func normalizeBeautyList(field string, values, allowed []string) ([]string, error) {
+	result := []string{}
+	seen := map[string]bool{}
+	for _, v := range values {
+		if err := validateBeautyValue(field, v, allowed); err != nil {
+			return nil, err
+		}
+		if v != "" && !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+func normalizeAllergies(values []string) ([]string, error) {
+	result := []string{}
+	seen := map[string]bool{}
+	for _, v := range values {
+		v = models.NormalizeINCIEntry(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		if len(v) > maxAllergenLength {
+			return nil, fmt.Errorf("allergen name is too long")
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	if len(result) > maxProfileAllergies {
+		return nil, fmt.Errorf("at most %d allergies are allowed", maxProfileAllergies)
+	}
+	return result, nil
+}
@@ -5,42 +5,195 @@ import (
 	"fmt"
 
 	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
 	"1kosmetika-marketplace-backend/repositories"
 )
 
+const (
+	CartWarningPriceChanged    = "price_changed"
+	CartWarningQuantityReduced = "quantity_reduced"
+	CartWarningUnavailable     = "unavailable"
+)
+
+// CartLineWarning описывает, что изменилось в строке корзины при последней проверке.
+type CartLineWarning struct {
+	ItemID      uint         `json:"item_id"`
+	ProductID   uint         `json:"product_id"`
+	Code        string       `json:"code"` // price_changed, quantity_reduced, unavailable
+	Message     string       `json:"message"`
+	OldPrice    money.Amount `json:"old_price,omitempty"`
+	NewPrice    money.Amount `json:"new_price,omitempty"`
+	OldQuantity int          `json:"old_quantity,omitempty"`
+	NewQuantity int          `json:"new_quantity,omitempty"`
+}
+
+// CartSummary — корзина с пересчитанными на сервере итогами.
+// Недоступные позиции остаются в корзине, но не входят в итог.
+type CartSummary struct {
+	*models.Cart
+	Warnings   []CartLineWarning `json:"warnings"`
+	Subtotal   money.Amount      `json:"subtotal"`
+	TotalItems int               `json:"total_items"`
+	Display    *CartDisplay      `json:"display,omitempty"`
+}
+
+// CartDisplay — суммы корзины в валюте отображения.
+type CartDisplay struct {
+	Currency string            `json:"currency"`
+	Subtotal money.Amount      `json:"subtotal"`
+	Items    []CartItemDisplay `json:"items"`
+}
+
+type CartItemDisplay struct {
+	ItemID    uint         `json:"item_id"`
+	UnitPrice money.Amount `json:"unit_price"`
+	Price     money.Amount `json:"price"`
+}
+
+// ApplyCurrency пересчитывает строки по отдельности; итог — сумма
+// пересчитанных строк, чтобы он сходился с тем, что видит покупатель.
+func (s *CartSummary) ApplyCurrency(conv *money.Converter) {
+	if conv == nil || s.Cart == nil {
+		return
+	}
+	display := &CartDisplay{Currency: conv.Currency, Items: make([]CartItemDisplay, 0, len(s.Items))}
+	for i := range s.Items {
+		item := &s.Items[i]
+		item.Product.ApplyCurrency(conv)
+		line := CartItemDisplay{
+			ItemID:    item.ID,
+			UnitPrice: conv.Convert(item.UnitPrice),
+			Price:     conv.Convert(item.UnitPrice).Mul(item.Quantity),
+		}
+		display.Subtotal += line.Price
+		display.Items = append(display.Items, line)
+	}
+	s.Display = display
+}
+
+type CartItemInput struct {
+	ProductID uint `json:"product_id"`
+	Quantity  int  `json:"quantity"`
+}
+
 type CartService interface {
-	GetCart(userID uint) (*models.Cart, error)
+	GetCart(userID uint) (*CartSummary, error)
 	AddToCart(userID uint, productID uint, quantity int) error
 	UpdateCartItem(userID uint, itemID uint, quantity int) error
 	RemoveFromCart(userID uint, itemID uint) error
 	ClearCart(userID uint) error
+	SetCart(userID uint, items []CartItemInput) (*CartSummary, error)
+	MoveToFavorites(userID uint, itemID uint) error
+	MergeCart(userID uint, items []CartItemInput) (*CartSummary, error)
+
+	GetGuestCart(token string) (*CartSummary, error)
+	AddToGuestCart(token string, productID uint, quantity int) error
+	UpdateGuestCartItem(token string, itemID uint, quantity int) error
+	RemoveFromGuestCart(token string, itemID uint) error
+	ClearGuestCart(token string) error
+	MergeGuestCart(token string, userID uint) (*CartSummary, error)
 }
 
 type cartService struct {
-	cartRepo    repositories.CartRepository
-	productRepo repositories.ProductRepository
+	cartRepo        repositories.CartRepository
+	productRepo     repositories.ProductRepository
+	favoriteService FavoriteService
 }
 
-func NewCartService(cartRepo repositories.CartRepository, productRepo repositories.ProductRepository) CartService {
+func NewCartService(cartRepo repositories.CartRepository, productRepo repositories.ProductRepository, favoriteService FavoriteService) CartService {
 	return &cartService{
-		cartRepo:    cartRepo,
-		productRepo: productRepo,
+		cartRepo:        cartRepo,
+		productRepo:     productRepo,
+		favoriteService: favoriteService,
 	}
 }
 
 // Возвращаем корзину; если не найдена — "пустая" структура (ID=0, Items=[]), без ошибки.
-func (s *cartService) GetCart(userID uint) (*models.Cart, error) {
+// Каждая строка сверяется с текущей ценой и остатком товара.
+func (s *cartService) GetCart(userID uint) (*CartSummary, error) {
 	cart, err := s.cartRepo.GetCartWithItems(userID)
 	if err != nil {
-		return &models.Cart{
-			UserID: userID,
+		cart = &models.Cart{
+			UserID: &userID,
 			Items:  []models.CartItem{},
-		}, nil
+		}
 	}
-	return cart, nil
+	return s.revalidate(cart)
+}
+
+func (s *cartService) revalidate(cart *models.Cart) (*CartSummary, error) {
+	summary := &CartSummary{Cart: cart, Warnings: []CartLineWarning{}}
+
+	for i := range cart.Items {
+		item := &cart.Items[i]
+		product := item.Product
+
+		// Удалённый товар не подгружается через Preload, поэтому Product.ID == 0.
+		if product.ID == 0 || product.Status != models.ProductStatusActive || product.Stock <= 0 {
+			summary.Warnings = append(summary.Warnings, CartLineWarning{
+				ItemID:    item.ID,
+				ProductID: item.ProductID,
+				Code:      CartWarningUnavailable,
+				Message:   "product is no longer available",
+			})
+			continue
+		}
+
+		changed := false
+		if item.Quantity > product.Stock {
+			summary.Warnings = append(summary.Warnings, CartLineWarning{
+				ItemID:      item.ID,
+				ProductID:   item.ProductID,
+				Code:        CartWarningQuantityReduced,
+				Message:     "quantity reduced to available stock",
+				OldQuantity: item.Quantity,
+				NewQuantity: product.Stock,
+			})
+			item.Quantity = product.Stock
+			changed = true
+		}
+		if item.UnitPrice != product.Price {
+			// Строки, сохранённые до появления unit_price, пересчитываются молча.
+			if item.UnitPrice > 0 {
+				summary.Warnings = append(summary.Warnings, CartLineWarning{
+					ItemID:    item.ID,
+					ProductID: item.ProductID,
+					Code:      CartWarningPriceChanged,
+					Message:   "price has changed",
+					OldPrice:  item.UnitPrice,
+					NewPrice:  product.Price,
+				})
+			}
+			item.UnitPrice = product.Price
+			changed = true
+		}
+		if lineTotal := item.UnitPrice.Mul(item.Quantity); item.Price != lineTotal {
+			item.Price = lineTotal
+			changed = true
+		}
+
+		if changed {
+			if err := s.cartRepo.UpdateCartItem(item); err != nil {
+				return nil, fmt.Errorf("failed to update cart item: %w", err)
+			}
+		}
+
+		summary.Subtotal += item.Price
+		summary.TotalItems += item.Quantity
+	}
+
+	return summary, nil
 }
 
 func (s *cartService) AddToCart(userID uint, productID uint, quantity int) error {
+	cart, err := s.findOrCreateCart(userID)
+	if err != nil {
+		return err
+	}
+	return s.addItem(cart, productID, quantity)
+}
+
+func (s *cartService) addItem(cart *models.Cart, productID uint, quantity int) error {
 	if quantity <= 0 {
 		quantity = 1
 	}
@@ -49,18 +202,13 @@ func (s *cartService) AddToCart(userID uint, productID uint, quantity int) error
 	if err != nil {
 		return fmt.Errorf("product not found")
 	}
+	if product.Status != models.ProductStatusActive {
+		return fmt.Errorf("product is not available")
+	}
 	if product.Stock < quantity {
 		return fmt.Errorf("not enough stock available")
 	}
 
-	cart, err := s.cartRepo.FindByUserID(userID)
-	if err != nil {
-		cart = &models.Cart{UserID: userID}
-		if err := s.cartRepo.Create(cart); err != nil {
-			return fmt.Errorf("failed to create cart")
-		}
-	}
-
 	existingItem, err := s.cartRepo.FindCartItem(cart.ID, productID)
 	if err == nil {
 		newQuantity := existingItem.Quantity + quantity
@@ -68,7 +216,8 @@ func (s *cartService) AddToCart(userID uint, productID uint, quantity int) error
 			return fmt.Errorf("not enough stock available")
 		}
 		existingItem.Quantity = newQuantity
-		existingItem.Price = product.Price * float64(newQuantity)
+		existingItem.UnitPrice = product.Price
+		existingItem.Price = product.Price.Mul(newQuantity)
 		return s.cartRepo.UpdateCartItem(existingItem)
 	}
 
@@ -76,7 +225,8 @@ func (s *cartService) AddToCart(userID uint, productID uint, quantity int) error
 		CartID:    cart.ID,
 		ProductID: productID,
 		Quantity:  quantity,
-		Price:     product.Price * float64(quantity),
+		UnitPrice: product.Price,
+		Price:     product.Price.Mul(quantity),
 	}
 	return s.cartRepo.CreateCartItem(cartItem)
 }
@@ -90,6 +240,13 @@ func (s *cartService) UpdateCartItem(userID uint, itemID uint, quantity int) err
 	if err != nil {
 		return fmt.Errorf("cart not found")
 	}
+	return s.updateItem(cart, itemID, quantity)
+}
+
+func (s *cartService) updateItem(cart *models.Cart, itemID uint, quantity int) error {
+	if quantity <= 0 {
+		return fmt.Errorf("quantity must be >= 1")
+	}
 
 	cartItem, err := s.cartRepo.FindCartItemByID(itemID)
 	if err != nil {
@@ -103,12 +260,16 @@ func (s *cartService) UpdateCartItem(userID uint, itemID uint, quantity int) err
 	if err != nil {
 		return fmt.Errorf("product not found")
 	}
+	if product.Status != models.ProductStatusActive {
+		return fmt.Errorf("product is not available")
+	}
 	if product.Stock < quantity {
 		return fmt.Errorf("not enough stock available")
 	}
 
 	cartItem.Quantity = quantity
-	cartItem.Price = product.Price * float64(quantity)
+	cartItem.UnitPrice = product.Price
+	cartItem.Price = product.Price.Mul(quantity)
 	return s.cartRepo.UpdateCartItem(cartItem)
 }
 
@@ -126,3 +287,237 @@ func (s *cartService) ClearCart(userID uint) error {
 	}
 	return s.cartRepo.ClearCart(cart.ID)
 }
+
+// Полностью заменяет содержимое корзины. Проверка всех строк выполняется до записи,
+// поэтому при ошибке корзина не меняется.
+func (s *cartService) SetCart(userID uint, items []CartItemInput) (*CartSummary, error) {
+	quantities := make(map[uint]int)
+	order := make([]uint, 0, len(items))
+	for _, in := range items {
+		if in.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity must be >= 1")
+		}
+		if _, ok := quantities[in.ProductID]; !ok {
+			order = append(order, in.ProductID)
+		}
+		quantities[in.ProductID] += in.Quantity
+	}
+
+	newItems := make([]models.CartItem, 0, len(order))
+	for _, productID := range order {
+		quantity := quantities[productID]
+		product, err := s.productRepo.FindByID(productID)
+		if err != nil {
+			return nil, fmt.Errorf("product %d not found", productID)
+		}
+		if product.Status != models.ProductStatusActive {
+			return nil, fmt.Errorf("product %q is not available", product.Name)
+		}
+		if product.Stock < quantity {
+			return nil, fmt.Errorf("not enough stock available for %q", product.Name)
+		}
+		newItems = append(newItems, models.CartItem{
+			ProductID: productID,
+			Quantity:  quantity,
+			UnitPrice: product.Price,
+			Price:     product.Price.Mul(quantity),
+		})
+	}
+
+	cart, err := s.findOrCreateCart(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cartRepo.ReplaceCartItems(cart.ID, newItems); err != nil {
+		return nil, fmt.Errorf("failed to update cart: %w", err)
+	}
+	return s.GetCart(userID)
+}
+
+func (s *cartService) MoveToFavorites(userID uint, itemID uint) error {
+	cart, err := s.cartRepo.FindByUserID(userID)
+	if err != nil {
+		return fmt.Errorf("cart item not found")
+	}
+	item, err := s.cartRepo.FindCartItemByID(itemID)
+	if err != nil || item.CartID != cart.ID {
+		return fmt.Errorf("cart item not found")
+	}
+
+	isFavorite, err := s.favoriteService.IsFavorite(userID, item.ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to check favorites")
+	}
+	if !isFavorite {
+		if err := s.favoriteService.AddFavorite(userID, item.ProductID); err != nil {
+			return err
+		}
+	}
+
+	return s.cartRepo.DeleteCartItem(item.ID)
+}
+
+// MergeCart добавляет позиции анонимной корзины в корзину пользователя.
+// Количество складывается и ограничивается остатком; недоступные товары пропускаются.
+// Все отклонения возвращаются в warnings итоговой корзины.
+func (s *cartService) MergeCart(userID uint, items []CartItemInput) (*CartSummary, error) {
+	cart, err := s.findOrCreateCart(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []CartLineWarning
+	for _, in := range items {
+		if warning := s.mergeItem(cart, in); warning != nil {
+			warnings = append(warnings, *warning)
+		}
+	}
+
+	summary, err := s.GetCart(userID)
+	if err != nil {
+		return nil, err
+	}
+	summary.Warnings = append(warnings, summary.Warnings...)
+	return summary, nil
+}
+
+func (s *cartService) mergeItem(cart *models.Cart, in CartItemInput) *CartLineWarning {
+	if in.Quantity <= 0 {
+		return nil
+	}
+
+	product, err := s.productRepo.FindByID(in.ProductID)
+	if err != nil || product.Status != models.ProductStatusActive || product.Stock <= 0 {
+		return &CartLineWarning{
+			ProductID: in.ProductID,
+			Code:      CartWarningUnavailable,
+			Message:   "product is no longer available",
+		}
+	}
+
+	existing, err := s.cartRepo.FindCartItem(cart.ID, in.ProductID)
+	if err != nil {
+		existing = &models.CartItem{CartID: cart.ID, ProductID: in.ProductID}
+	}
+
+	wanted := existing.Quantity + in.Quantity
+	quantity := wanted
+	if quantity > product.Stock {
+		quantity = product.Stock
+	}
+
+	existing.Quantity = quantity
+	existing.UnitPrice = product.Price
+	existing.Price = product.Price.Mul(quantity)
+	if existing.ID == 0 {
+		err = s.cartRepo.CreateCartItem(existing)
+	} else {
+		err = s.cartRepo.UpdateCartItem(existing)
+	}
+	if err != nil {
+		return &CartLineWarning{
+			ProductID: in.ProductID,
+			Code:      CartWarningUnavailable,
+			Message:   "failed to add product to cart",
+		}
+	}
+
+	if quantity < wanted {
+		return &CartLineWarning{
+			ItemID:      existing.ID,
+			ProductID:   in.ProductID,
+			Code:        CartWarningQuantityReduced,
+			Message:     "quantity reduced to available stock",
+			OldQuantity: wanted,
+			NewQuantity: quantity,
+		}
+	}
+	return nil
+}
+
+func (s *cartService) findOrCreateCart(userID uint) (*models.Cart, error) {
+	cart, err := s.cartRepo.FindByUserID(userID)
+	if err == nil {
+		return cart, nil
+	}
+	cart = &models.Cart{UserID: &userID}
+	if err := s.cartRepo.Create(cart); err != nil {
+		return nil, fmt.Errorf("failed to create cart")
+	}
+	return cart, nil
+}
+
+// ---- Гостевые корзины ----
+// Гостевая корзина определяется подписанным токеном из cookie и не имеет владельца.
+
+func (s *cartService) GetGuestCart(token string) (*CartSummary, error) {
+	cart, err := s.cartRepo.FindByGuestToken(token)
+	if err != nil {
+		cart = &models.Cart{Items: []models.CartItem{}}
+	}
+	return s.revalidate(cart)
+}
+
+func (s *cartService) AddToGuestCart(token string, productID uint, quantity int) error {
+	cart, err := s.cartRepo.FindByGuestToken(token)
+	if err != nil {
+		cart = &models.Cart{GuestToken: token}
+		if err := s.cartRepo.Create(cart); err != nil {
+			return fmt.Errorf("failed to create cart")
+		}
+	}
+	return s.addItem(cart, productID, quantity)
+}
+
+func (s *cartService) UpdateGuestCartItem(token string, itemID uint, quantity int) error {
+	cart, err := s.cartRepo.FindByGuestToken(token)
+	if err != nil {
+		return fmt.Errorf("cart not found")
+	}
+	return s.updateItem(cart, itemID, quantity)
+}
+
+// Идемпотентно, как и RemoveFromCart.
+func (s *cartService) RemoveFromGuestCart(token string, itemID uint) error {
+	cart, err := s.cartRepo.FindByGuestToken(token)
+	if err != nil {
+		return nil
+	}
+	for _, item := range cart.Items {
+		if item.ID == itemID {
+			return s.cartRepo.DeleteCartItem(itemID)
+		}
+	}
+	return nil
+}
+
+func (s *cartService) ClearGuestCart(token string) error {
+	cart, err := s.cartRepo.FindByGuestToken(token)
+	if err != nil {
+		return nil
+	}
+	return s.cartRepo.ClearCart(cart.ID)
+}
+
+// MergeGuestCart переносит гостевую корзину в корзину пользователя по правилам MergeCart
+// и удаляет гостевую. Если гостевой корзины нет, возвращает nil.
+func (s *cartService) MergeGuestCart(token string, userID uint) (*CartSummary, error) {
+	guestCart, err := s.cartRepo.FindByGuestToken(token)
+	if err != nil || len(guestCart.Items) == 0 {
+		return nil, nil
+	}
+
+	inputs := make([]CartItemInput, 0, len(guestCart.Items))
+	for _, item := range guestCart.Items {
+		inputs = append(inputs, CartItemInput{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+
+	summary, err := s.MergeCart(userID, inputs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cartRepo.DeleteCart(guestCart.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete guest cart: %w", err)
+	}
+	return summary, nil
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"fmt"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
+	"1kosmetika-marketplace-backend/repositories"
+)
+
+// CurrencyService хранит курсы валют отображения. Цены и заказы всегда
+// считаются в базовой валюте; пересчёт нужен только для показа на витрине.
+type CurrencyService interface {
+	GetRates() ([]models.ExchangeRate, error)
+	SetRate(currency string, rate float64) (*models.ExchangeRate, error)
+	DeleteRate(currency string) error
+	// Converter возвращает nil для базовой валюты или пустого кода.
+	Converter(currency string) (*money.Converter, error)
+}
+
+type currencyService struct {
+	rateRepo repositories.ExchangeRateRepository
+}
+
+func NewCurrencyService(rateRepo repositories.ExchangeRateRepository) CurrencyService {
+	return &currencyService{rateRepo: rateRepo}
+}
+
+func (s *currencyService) GetRates() ([]models.ExchangeRate, error) {
+	return s.rateRepo.FindAll()
+}
+
+func (s *currencyService) SetRate(currency string, rate float64) (*models.ExchangeRate, error) {
+	code, err := money.NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	if code == money.BaseCurrency() {
+		return nil, fmt.Errorf("base currency rate is always 1")
+	}
+	if rate <= 0 {
+		return nil, fmt.Errorf("rate must be positive")
+	}
+
+	if err := s.rateRepo.Upsert(&models.ExchangeRate{Currency: code, Rate: rate}); err != nil {
+		return nil, err
+	}
+	return s.rateRepo.FindByCurrency(code)
+}
+
+func (s *currencyService) DeleteRate(currency string) error {
+	code, err := money.NormalizeCurrency(currency)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.rateRepo.Delete(code)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("exchange rate not found")
+	}
+	return nil
+}
+
+func (s *currencyService) Converter(currency string) (*money.Converter, error) {
+	if currency == "" {
+		return nil, nil
+	}
+	code, err := money.NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	if code == money.BaseCurrency() {
+		return nil, nil
+	}
+
+	rate, err := s.rateRepo.FindByCurrency(code)
+	if err != nil {
+		return nil, fmt.Errorf("currency %s is not supported", code)
+	}
+	return &money.Converter{Currency: code, Rate: rate.Rate}, nil
+}
@@ -2,26 +2,53 @@ package services
 
 import (
 	"fmt"
+	"html"
+	"log"
+
 	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
 	"1kosmetika-marketplace-backend/repositories"
+	"1kosmetika-marketplace-backend/utils"
 )
 
+type FavoriteAlertsInput struct {
+	PriceDrop   bool `json:"price_drop"`
+	BackInStock bool `json:"back_in_stock"`
+	Email       bool `json:"email"`
+}
+
 type FavoriteService interface {
 	GetUserFavorites(userID uint) ([]models.Favorite, error)
 	AddFavorite(userID, productID uint) error
 	RemoveFavorite(userID, productID uint) error
 	IsFavorite(userID, productID uint) (bool, error)
+	SetAlerts(userID, productID uint, input FavoriteAlertsInput) error
+	// SendAlerts сравнивает цену и наличие товаров из избранного с последним
+	// уведомлённым состоянием и сообщает о снижении цены и поступлении.
+	SendAlerts() error
 }
 
 type favoriteService struct {
-	favoriteRepo repositories.FavoriteRepository
-	productRepo  repositories.ProductRepository
+	favoriteRepo     repositories.FavoriteRepository
+	productRepo      repositories.ProductRepository
+	wishlistRepo     repositories.WishlistRepository
+	notificationRepo repositories.NotificationRepository
+	userRepo         repositories.UserRepository
 }
 
-func NewFavoriteService(favoriteRepo repositories.FavoriteRepository, productRepo repositories.ProductRepository) FavoriteService {
+func NewFavoriteService(
+	favoriteRepo repositories.FavoriteRepository,
+	productRepo repositories.ProductRepository,
+	wishlistRepo repositories.WishlistRepository,
+	notificationRepo repositories.NotificationRepository,
+	userRepo repositories.UserRepository,
+) FavoriteService {
 	return &favoriteService{
-		favoriteRepo: favoriteRepo,
-		productRepo:  productRepo,
+		favoriteRepo:     favoriteRepo,
+		productRepo:      productRepo,
+		wishlistRepo:     wishlistRepo,
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
 	}
 }
 
@@ -29,6 +56,7 @@ func (s *favoriteService) GetUserFavorites(userID uint) ([]models.Favorite, erro
 	return s.favoriteRepo.FindByUserID(userID)
 }
 
+// AddFavorite кладёт товар в список по умолчанию.
 func (s *favoriteService) AddFavorite(userID, productID uint) error {
 
 	_, err := s.productRepo.FindByID(productID)
@@ -36,7 +64,6 @@ func (s *favoriteService) AddFavorite(userID, productID uint) error {
 		return fmt.Errorf("product not found")
 	}
 
-
 	exists, err := s.favoriteRepo.Exists(userID, productID)
 	if err != nil {
 		return fmt.Errorf("failed to check favorites")
@@ -45,14 +72,21 @@ func (s *favoriteService) AddFavorite(userID, productID uint) error {
 		return fmt.Errorf("product already in favorites")
 	}
 
+	wishlist, err := s.wishlistRepo.FindOrCreateDefault(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get wishlist")
+	}
+
 	favorite := &models.Favorite{
-		UserID:    userID,
-		ProductID: productID,
+		UserID:     userID,
+		WishlistID: &wishlist.ID,
+		ProductID:  productID,
 	}
 
 	return s.favoriteRepo.Create(favorite)
 }
 
+// RemoveFavorite убирает товар из всех списков пользователя.
 func (s *favoriteService) RemoveFavorite(userID, productID uint) error {
 
 	exists, err := s.favoriteRepo.Exists(userID, productID)
@@ -68,4 +102,111 @@ func (s *favoriteService) RemoveFavorite(userID, productID uint) error {
 
 func (s *favoriteService) IsFavorite(userID, productID uint) (bool, error) {
 	return s.favoriteRepo.Exists(userID, productID)
-}
\ No newline at end of file
+}
+
+// SetAlerts включает уведомления для товара во всех списках пользователя.
+// Точкой отсчёта становятся текущие цена и наличие.
+func (s *favoriteService) SetAlerts(userID, productID uint, input FavoriteAlertsInput) error {
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil {
+		return fmt.Errorf("product not found")
+	}
+
+	updated, err := s.favoriteRepo.UpdateAlerts(userID, productID, map[string]interface{}{
+		"price_drop_alert": input.PriceDrop,
+		"stock_alert":      input.BackInStock,
+		"alert_email":      input.Email,
+		"alert_price":      product.Price,
+		"alert_in_stock":   product.Stock > 0,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update alerts")
+	}
+	if !updated {
+		return fmt.Errorf("product not in favorites")
+	}
+	return nil
+}
+
+func (s *favoriteService) SendAlerts() error {
+	favorites, err := s.favoriteRepo.FindWithAlerts()
+	if err != nil {
+		return fmt.Errorf("failed to load favorite alerts: %w", err)
+	}
+
+	// Товар может лежать в нескольких списках: одно уведомление на пользователя и товар.
+	type key struct{ userID, productID uint }
+	notified := make(map[key]bool)
+	users := make(map[uint]*models.User)
+	sent := 0
+
+	for i := range favorites {
+		fav := &favorites[i]
+		p := &fav.Product
+		if p.ID == 0 || p.Status != models.ProductStatusActive {
+			continue
+		}
+
+		inStock := p.Stock > 0
+		priceDropped := fav.PriceDropAlert && p.Price < fav.AlertPrice
+		restocked := fav.StockAlert && inStock && !fav.AlertInStock
+		oldPrice := fav.AlertPrice
+
+		if p.Price != fav.AlertPrice || inStock != fav.AlertInStock {
+			if err := s.favoriteRepo.UpdateAlertState(fav.ID, p.Price, inStock); err != nil {
+				log.Printf("❌ Failed to update alert state for favorite %d: %v", fav.ID, err)
+				continue
+			}
+		}
+
+		k := key{fav.UserID, p.ID}
+		if !(priceDropped || restocked) || notified[k] {
+			continue
+		}
+		notified[k] = true
+
+		title, message := favoriteAlertText(p, oldPrice, priceDropped, restocked)
+		_ = s.notificationRepo.Create(&models.Notification{
+			UserID:  fav.UserID,
+			Title:   title,
+			Message: message,
+			Type:    "info",
+		})
+		sent++
+
+		if !fav.AlertEmail {
+			continue
+		}
+		user, ok := users[fav.UserID]
+		if !ok {
+			user, _ = s.userRepo.FindByID(fav.UserID)
+			users[fav.UserID] = user
+		}
+		if user == nil || user.Email == "" {
+			continue
+		}
+		body := fmt.Sprintf("<h2>%s</h2><p>%s</p>", html.EscapeString(title), html.EscapeString(message))
+		if err := utils.SendEmail(user.Email, title, body); err != nil {
+			log.Printf("❌ Failed to send favorite alert email to user %d: %v", user.ID, err)
+		}
+	}
+
+	if sent > 0 {
+		log.Printf("💖 Favorite alerts sent: %d", sent)
+	}
+	return nil
+}
+
+func favoriteAlertText(p *models.Product, oldPrice money.Amount, priceDropped, restocked bool) (string, string) {
+	switch {
+	case priceDropped && restocked:
+		return "Товар из избранного снова в продаже и подешевел",
+			fmt.Sprintf("«%s» снова в наличии, цена снизилась: %s → %s", p.Name, money.Format(oldPrice), money.Format(p.Price))
+	case priceDropped:
+		return "Цена товара из избранного снизилась",
+			fmt.Sprintf("«%s»: %s → %s", p.Name, money.Format(oldPrice), money.Format(p.Price))
+	default:
+		return "Товар из избранного снова в наличии",
+			fmt.Sprintf("«%s» снова можно купить по цене %s.", p.Name, money.Format(p.Price))
+	}
+}
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/repositories"
+
+	"gorm.io/gorm"
+)
+
+// Колонки CSV для импорта; обязательна только inci_name, бытовые названия
+// перечисляются через «;».
+var ingredientCSVColumns = []string{
+	"inci_name", "common_names", "description",
+	"is_allergen", "is_fragrance", "is_paraben", "is_sulfate", "is_silicone",
+}
+
+// IngredientImportResult — итог импорта; строки с ошибками пропускаются.
+type IngredientImportResult struct {
+	Created int      `json:"created"`
+	Updated int      `json:"updated"`
+	Errors  []string `json:"errors"`
+}
+
+// ProductComposition — состав товара; Created — ингредиенты, которых не было
+// в базе и которые добавлены без признаков при разборе текста.
+type ProductComposition struct {
+	Ingredients []models.Ingredient `json:"ingredients"`
+	Text        string              `json:"text"`
+	Created     []string            `json:"created,omitempty"`
+}
+
+type IngredientService interface {
+	ListIngredients(search string, page, limit int) ([]models.Ingredient, int64, error)
+	GetIngredient(id uint) (*models.Ingredient, error)
+	CreateIngredient(ingredient *models.Ingredient) error
+	UpdateIngredient(id uint, ingredient *models.Ingredient) (*models.Ingredient, error)
+	DeleteIngredient(id uint) error
+	// ImportCSV добавляет новые ингредиенты и обновляет существующие по INCI.
+	ImportCSV(r io.Reader) (*IngredientImportResult, error)
+	GetProductComposition(productID uint) (*ProductComposition, error)
+	SetProductIngredients(productID uint, ingredientIDs []uint) (*ProductComposition, error)
+	// SetProductINCI размечает состав по тексту с упаковки (через запятую).
+	SetProductINCI(productID uint, text string) (*ProductComposition, error)
+	// GetProductsIngredients — составы товаров выдачи для пометки аллергенов.
+	GetProductsIngredients(productIDs []uint) (map[uint][]models.Ingredient, error)
+}
+
+type ingredientService struct {
+	ingredientRepo repositories.IngredientRepository
+	productRepo    repositories.ProductRepository
+}
+
+func NewIngredientService(
+	ingredientRepo repositories.IngredientRepository,
+	productRepo repositories.ProductRepository,
+) IngredientService {
+	return &ingredientService{
+		ingredientRepo: ingredientRepo,
+		productRepo:    productRepo,
+	}
+}
+
+func (s *ingredientService) ListIngredients(search string, page, limit int) ([]models.Ingredient, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+	return s.ingredientRepo.FindAll(strings.TrimSpace(search), page, limit)
+}
+
+func (s *ingredientService) GetIngredient(id uint) (*models.Ingredient, error) {
+	ingredient, err := s.ingredientRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("ingredient not found")
+	}
+	return ingredient, nil
+}
+
+func (s *ingredientService) CreateIngredient(ingredient *models.Ingredient) error {
+	if err := normalizeIngredient(ingredient); err != nil {
+		return err
+	}
+	if existing, err := s.ingredientRepo.FindByName(ingredient.INCIName); err == nil &&
+		strings.EqualFold(existing.INCIName, ingredient.INCIName) {
+		return fmt.Errorf("ingredient %q already exists", ingredient.INCIName)
+	}
+	ingredient.ID = 0
+	return s.ingredientRepo.Create(ingredient)
+}
+
+func (s *ingredientService) UpdateIngredient(id uint, ingredient *models.Ingredient) (*models.Ingredient, error) {
+	existing, err := s.ingredientRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("ingredient not found")
+	}
+	if err := normalizeIngredient(ingredient); err != nil {
+		return nil, err
+	}
+	if other, err := s.ingredientRepo.FindByName(ingredient.INCIName); err == nil && other.ID != id &&
+		strings.EqualFold(other.INCIName, ingredient.INCIName) {
+		return nil, fmt.Errorf("ingredient %q already exists", ingredient.INCIName)
+	}
+
+	existing.INCIName = ingredient.INCIName
+	existing.CommonNames = ingredient.CommonNames
+	existing.Description = ingredient.Description
+	existing.IsAllergen = ingredient.IsAllergen
+	existing.IsFragrance = ingredient.IsFragrance
+	existing.IsParaben = ingredient.IsParaben
+	existing.IsSulfate = ingredient.IsSulfate
+	existing.IsSilicone = ingredient.IsSilicone
+	if err := s.ingredientRepo.Update(existing); err != nil {
+		return nil, fmt.Errorf("failed to update ingredient: %w", err)
+	}
+	return existing, nil
+}
+
+func (s *ingredientService) DeleteIngredient(id uint) error {
+	if err := s.ingredientRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("ingredient not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *ingredientService) ImportCSV(r io.Reader) (*IngredientImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["inci_name"]; !ok {
+		return nil, fmt.Errorf("CSV must have columns: %s", strings.Join(ingredientCSVColumns, ", "))
+	}
+
+	result := &IngredientImportResult{Errors: []string{}}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		ingredient, err := ingredientFromCSV(record, columns)
+		if err == nil {
+			err = normalizeIngredient(ingredient)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+
+		existing, findErr := s.ingredientRepo.FindByName(ingredient.INCIName)
+		if findErr == nil && strings.EqualFold(existing.INCIName, ingredient.INCIName) {
+			ingredient.ID = existing.ID
+			ingredient.CreatedAt = existing.CreatedAt
+			err = s.ingredientRepo.Update(ingredient)
+			if err == nil {
+				result.Updated++
+			}
+		} else {
+			err = s.ingredientRepo.Create(ingredient)
+			if err == nil {
+				result.Created++
+			}
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("line %d: %v", line, err))
+		}
+	}
+	return result, nil
+}
+
+func ingredientFromCSV(record []string, columns map[string]int) (*models.Ingredient, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	flag := func(name string) (bool, error) {
+		value := field(name)
+		if value == "" {
+			return false, nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("%s must be true or false", name)
+		}
+		return b, nil
+	}
+
+	ingredient := &models.Ingredient{
+		INCIName:    field("inci_name"),
+		CommonNames: strings.Split(field("common_names"), ";"),
+		Description: field("description"),
+	}
+	var err error
+	if ingredient.IsAllergen, err = flag("is_allergen"); err != nil {
+		return nil, err
+	}
+	if ingredient.IsFragrance, err = flag("is_fragrance"); err != nil {
+		return nil, err
+	}
+	if ingredient.IsParaben, err = flag("is_paraben"); err != nil {
+		return nil, err
+	}
+	if ingredient.IsSulfate, err = flag("is_sulfate"); err != nil {
+		return nil, err
+	}
+	if ingredient.IsSilicone, err = flag("is_silicone"); err != nil {
+		return nil, err
+	}
+	return ingredient, nil
+}
+
+// normalizeIngredient убирает лишние пробелы и повторы бытовых названий.
+func normalizeIngredient(ingredient *models.Ingredient) error {
+	ingredient.INCIName = strings.Join(strings.Fields(ingredient.INCIName), " ")
+	if ingredient.INCIName == "" {
+		return fmt.Errorf("inci_name is required")
+	}
+	if len(ingredient.INCIName) > 255 {
+		return fmt.Errorf("inci_name is too long")
+	}
+	ingredient.Description = strings.TrimSpace(ingredient.Description)
+
+	names := []string{}
+	seen := map[string]bool{strings.ToLower(ingredient.INCIName): true}
+	for _, name := range ingredient.CommonNames {
+		name = strings.Join(strings.Fields(name), " ")
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		names = append(names, name)
+	}
+	ingredient.CommonNames = names
+	return nil
+}
+
+func (s *ingredientService) GetProductComposition(productID uint) (*ProductComposition, error) {
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil || product.Status != models.ProductStatusActive {
+		return nil, fmt.Errorf("product not found")
+	}
+	ingredients, err := s.ingredientRepo.FindProductIngredients(productID)
+	if err != nil {
+		return nil, err
+	}
+	return &ProductComposition{Ingredients: ingredients, Text: product.Ingredients}, nil
+}
+
+func (s *ingredientService) GetProductsIngredients(productIDs []uint) (map[uint][]models.Ingredient, error) {
+	return s.ingredientRepo.FindIngredientsByProducts(productIDs)
+}
+
+func (s *ingredientService) SetProductIngredients(productID uint, ingredientIDs []uint) (*ProductComposition, error) {
+	if _, err := s.productRepo.FindByID(productID); err != nil {
+		return nil, fmt.Errorf("product not found")
+	}
+
+	ids := []uint{}
+	seen := map[uint]bool{}
+	for _, id := range ingredientIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	found, err := s.ingredientRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Ingredient, len(found))
+	for _, ingredient := range found {
+		byID[ingredient.ID] = ingredient
+	}
+
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		ingredient, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("ingredient %d not found", id)
+		}
+		names[i] = ingredient.INCIName
+	}
+	return s.replaceComposition(productID, ids, strings.Join(names, ", "), nil)
+}
+
+func (s *ingredientService) SetProductINCI(productID uint, text string) (*ProductComposition, error) {
+	if _, err := s.productRepo.FindByID(productID); err != nil {
+		return nil, fmt.Errorf("product not found")
+	}
+
+	var ids []uint
+	var names, created []string
+	seen := map[uint]bool{}
+	for _, name := range strings.Split(text, ",") {
+		name = strings.Join(strings.Fields(name), " ")
+		if name == "" {
+			continue
+		}
+		ingredient, err := s.ingredientRepo.FindByName(name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ingredient = &models.Ingredient{INCIName: name, CommonNames: []string{}}
+			if err = s.ingredientRepo.Create(ingredient); err == nil {
+				created = append(created, name)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve ingredient %q: %w", name, err)
+		}
+		if !seen[ingredient.ID] {
+			seen[ingredient.ID] = true
+			ids = append(ids, ingredient.ID)
+			names = append(names, name)
+		}
+	}
+	return s.replaceComposition(productID, ids, strings.Join(names, ", "), created)
+}
+
+func (s *ingredientService) replaceComposition(productID uint, ids []uint, text string, created []string) (*ProductComposition, error) {
+	if err := s.ingredientRepo.ReplaceProductIngredients(productID, ids, text); err != nil {
+		return nil, fmt.Errorf("failed to save product ingredients: %w", err)
+	}
+	ingredients, err := s.ingredientRepo.FindProductIngredients(productID)
+	if err != nil {
+		return nil, err
+	}
+	return &ProductComposition{Ingredients: ingredients, Text: text, Created: created}, nil
+}
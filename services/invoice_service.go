@@ -0,0 +1,268 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
+	"1kosmetika-marketplace-backend/repositories"
+	"1kosmetika-marketplace-backend/utils"
+)
+
+// CompanyInfo — реквизиты продавца, печатаются в шапке счёта.
+type CompanyInfo struct {
+	Name    string
+	Address string
+	TaxID   string
+	Phone   string
+	Email   string
+	// Префикс номера счёта, например INV → INV-000042.
+	InvoicePrefix string
+}
+
+type InvoiceService interface {
+	GetInvoice(orderID, userID uint, isAdmin bool) (*models.Invoice, error)
+	GenerateInvoice(orderID uint) (*models.Invoice, error)
+}
+
+type invoiceService struct {
+	invoiceRepo repositories.InvoiceRepository
+	orderRepo   repositories.OrderRepository
+	company     CompanyInfo
+}
+
+func NewInvoiceService(
+	invoiceRepo repositories.InvoiceRepository,
+	orderRepo repositories.OrderRepository,
+	company CompanyInfo,
+) InvoiceService {
+	return &invoiceService{
+		invoiceRepo: invoiceRepo,
+		orderRepo:   orderRepo,
+		company:     company,
+	}
+}
+
+func (s *invoiceService) GetInvoice(orderID, userID uint, isAdmin bool) (*models.Invoice, error) {
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("order not found")
+	}
+	if !isAdmin && (order.UserID == nil || *order.UserID != userID) {
+		return nil, fmt.Errorf("order not found")
+	}
+	return s.GenerateInvoice(orderID)
+}
+
+// GenerateInvoice возвращает уже выставленный счёт или создаёт новый.
+func (s *invoiceService) GenerateInvoice(orderID uint) (*models.Invoice, error) {
+	if invoice, err := s.invoiceRepo.FindByOrderID(orderID); err == nil {
+		return invoice, nil
+	}
+
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("order not found")
+	}
+	items, err := s.orderRepo.FindItems(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+
+	invoice, err := s.invoiceRepo.CreateForOrder(orderID, func(seq int64) (*models.Invoice, error) {
+		invoice := &models.Invoice{
+			Number:   fmt.Sprintf("%s-%06d", s.company.InvoicePrefix, seq),
+			IssuedAt: time.Now(),
+			Total:    order.Total,
+		}
+		invoice.PDF = renderInvoice(invoice, s.company, order, items)
+		return invoice, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create invoice: %w", err)
+	}
+	return invoice, nil
+}
+
+const (
+	invoiceMargin = 50.0
+	invoiceBottom = 70.0
+)
+
+func renderInvoice(invoice *models.Invoice, company CompanyInfo, order *models.Order, items []models.OrderProduct) []byte {
+	pdf := utils.NewPDF()
+	right := utils.PDFPageWidth - invoiceMargin
+	y := utils.PDFPageHeight - invoiceMargin
+
+	line := func(x float64, size float64, bold bool, text string) {
+		pdf.Text(x, y, size, bold, text)
+		y -= size + 5
+	}
+
+	line(invoiceMargin, 20, true, "INVOICE "+invoice.Number)
+	line(invoiceMargin, 10, false, "Date: "+invoice.IssuedAt.Format("02.01.2006"))
+	line(invoiceMargin, 10, false, fmt.Sprintf("Order: #%d from %s", order.ID, order.CreatedAt.Format("02.01.2006")))
+	y -= 10
+
+	top := y
+	line(invoiceMargin, 11, true, "Seller")
+	for _, text := range []string{company.Name, company.Address, taxIDLine(company.TaxID), company.Phone, company.Email} {
+		if text != "" {
+			line(invoiceMargin, 10, false, text)
+		}
+	}
+	sellerBottom := y
+
+	y = top
+	buyerX := utils.PDFPageWidth / 2
+	line(buyerX, 11, true, "Buyer")
+	for _, text := range buyerLines(order) {
+		if text != "" {
+			line(buyerX, 10, false, text)
+		}
+	}
+	if sellerBottom < y {
+		y = sellerBottom
+	}
+	y -= 15
+
+	// Правые края колонок: количество, цена, ставка, налог, сумма.
+	qtyX, priceX, rateX, taxX := 300.0, 370.0, 420.0, 480.0
+	header := func() {
+		pdf.Text(invoiceMargin, y, 10, true, "Item")
+		pdf.TextRight(qtyX, y, 10, true, "Qty")
+		pdf.TextRight(priceX, y, 10, true, "Price")
+		pdf.TextRight(rateX, y, 10, true, "VAT %")
+		pdf.TextRight(taxX, y, 10, true, "VAT")
+		pdf.TextRight(right, y, 10, true, "Amount")
+		y -= 6
+		pdf.Line(invoiceMargin, y, right, y)
+		y -= 14
+	}
+	header()
+
+	names := make(map[uint]string, len(order.Products))
+	for _, p := range order.Products {
+		names[p.ID] = p.Name
+	}
+	// Налог по ставкам для итоговой расшифровки, в порядке появления ставок.
+	var rates []float64
+	taxByRate := make(map[float64]money.Amount)
+	for _, item := range items {
+		if y < invoiceBottom {
+			pdf.AddPage()
+			y = utils.PDFPageHeight - invoiceMargin
+			header()
+		}
+		name := names[item.ProductID]
+		if name == "" {
+			name = fmt.Sprintf("Product #%d", item.ProductID)
+		}
+		pdf.Text(invoiceMargin, y, 10, false, truncateText(name, 10, qtyX-invoiceMargin-40))
+		pdf.TextRight(qtyX, y, 10, false, fmt.Sprintf("%d", item.Quantity))
+		pdf.TextRight(priceX, y, 10, false, item.Price.String())
+		pdf.TextRight(rateX, y, 10, false, formatRate(item.TaxRate))
+		pdf.TextRight(taxX, y, 10, false, item.TaxAmount.String())
+		pdf.TextRight(right, y, 10, false, item.Price.Mul(item.Quantity).String())
+		y -= 16
+
+		if _, ok := taxByRate[item.TaxRate]; !ok {
+			rates = append(rates, item.TaxRate)
+		}
+		taxByRate[item.TaxRate] += item.TaxAmount
+	}
+
+	if y < invoiceBottom+100 {
+		pdf.AddPage()
+		y = utils.PDFPageHeight - invoiceMargin
+	}
+	pdf.Line(invoiceMargin, y+8, right, y+8)
+	y -= 6
+
+	total := func(label, amount string, bold bool) {
+		pdf.TextRight(taxX, y, 10, bold, label)
+		pdf.TextRight(right, y, 10, bold, amount)
+		y -= 16
+	}
+	total("Subtotal:", order.Subtotal.String(), false)
+	taxLabel := "VAT %s%%:"
+	if order.PricesIncludeTax {
+		taxLabel = "incl. VAT %s%%:"
+	}
+	for _, rate := range rates {
+		if rate == 0 {
+			continue
+		}
+		total(fmt.Sprintf(taxLabel, formatRate(rate)), taxByRate[rate].String(), false)
+	}
+	if order.ShippingMethodName != "" {
+		total("Shipping ("+order.ShippingMethodName+"):", order.ShippingCost.String(), false)
+	}
+	currency := order.Currency
+	if currency == "" {
+		currency = money.BaseCurrency()
+	}
+	total("Total ("+currency+"):", order.Total.String(), true)
+
+	return pdf.Bytes()
+}
+
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
+
+func taxIDLine(taxID string) string {
+	if taxID == "" {
+		return ""
+	}
+	return "Tax ID: " + taxID
+}
+
+func buyerLines(order *models.Order) []string {
+	name := order.ShippingFullName
+	if name == "" {
+		name = order.GuestName
+	}
+	if name == "" {
+		name = order.User.FullName
+	}
+	email := order.GuestEmail
+	if email == "" {
+		email = order.User.Email
+	}
+	return []string{
+		name,
+		email,
+		order.ShippingPhone,
+		order.ShippingAddress,
+		joinNonEmpty(order.ShippingCity, order.ShippingRegion, order.ShippingPostalCode),
+	}
+}
+
+func joinNonEmpty(parts ...string) string {
+	out := ""
+	for _, p := range parts {
+		if p == "" {
+			continue
+		}
+		if out != "" {
+			out += ", "
+		}
+		out += p
+	}
+	return out
+}
+
+// Длинные названия обрезаются, чтобы не наезжать на соседнюю колонку.
+func truncateText(s string, size, width float64) string {
+	if utils.TextWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && utils.TextWidth(string(runes)+"...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
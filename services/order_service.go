@@ -1,16 +1,43 @@
 package services
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
 	"1kosmetika-marketplace-backend/repositories"
+	"1kosmetika-marketplace-backend/utils"
 )
 
+// AddressID и ShippingMethodID необязательны: без адреса берётся адрес
+// по умолчанию, без способа доставки заказ оформляется без её стоимости.
+type CreateOrderInput struct {
+	ProductIDs       []uint
+	AddressID        *uint
+	ShippingMethodID *uint
+}
+
+type GuestCheckoutInput struct {
+	Email            string
+	FullName         string
+	Phone            string
+	Region           string
+	City             string
+	Address          string
+	PostalCode       string
+	ShippingMethodID *uint
+}
+
 type OrderService interface {
-	CreateOrder(userID uint, productIDs []uint) (*models.Order, error)
+	CreateOrder(userID uint, input CreateOrderInput) (*models.Order, error)
+	CreateGuestOrder(guestToken string, input GuestCheckoutInput) (*models.Order, error)
 	GetUserOrders(userID uint) ([]models.Order, error)
-	GetOrderByID(id uint) (*models.Order, error)
+	GetOrderByID(id, userID uint, isAdmin bool) (*models.Order, error)
+	CancelOrder(orderID, userID uint, reason string) (*models.Order, error)
 	GetAllOrders() ([]models.Order, error)
 }
 
@@ -19,6 +46,11 @@ type orderService struct {
 	productRepo      repositories.ProductRepository
 	cartRepo         repositories.CartRepository
 	notificationRepo repositories.NotificationRepository
+	reminderRepo     repositories.CartReminderRepository
+	addressRepo      repositories.AddressRepository
+	shippingService  ShippingService
+	userRepo         repositories.UserRepository
+	taxService       TaxService
 }
 
 func NewOrderService(
@@ -26,27 +58,42 @@ func NewOrderService(
 	productRepo repositories.ProductRepository,
 	cartRepo repositories.CartRepository,
 	notificationRepo repositories.NotificationRepository,
+	reminderRepo repositories.CartReminderRepository,
+	addressRepo repositories.AddressRepository,
+	shippingService ShippingService,
+	userRepo repositories.UserRepository,
+	taxService TaxService,
 ) OrderService {
 	return &orderService{
 		orderRepo:        orderRepo,
 		productRepo:      productRepo,
 		cartRepo:         cartRepo,
 		notificationRepo: notificationRepo,
+		reminderRepo:     reminderRepo,
+		addressRepo:      addressRepo,
+		shippingService:  shippingService,
+		userRepo:         userRepo,
+		taxService:       taxService,
 	}
 }
 
-func (s *orderService) CreateOrder(userID uint, productIDs []uint) (*models.Order, error) {
+func (s *orderService) CreateOrder(userID uint, input CreateOrderInput) (*models.Order, error) {
 
-	products, err := s.productRepo.FindByIDs(productIDs)
+	products, err := s.productRepo.FindByIDs(input.ProductIDs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get products: %w", err)
 	}
-	if len(products) != len(productIDs) {
+	if len(products) != len(input.ProductIDs) {
 		return nil, fmt.Errorf("some products not found")
 	}
+	for _, p := range products {
+		if p.Status != models.ProductStatusActive {
+			return nil, fmt.Errorf("product %q is not available", p.Name)
+		}
+	}
 
 
-	var total float64
+	var total money.Amount
 	items := make([]models.OrderProduct, 0, len(products))
 	for _, p := range products {
 		total += p.Price
@@ -58,30 +105,53 @@ func (s *orderService) CreateOrder(userID uint, productIDs []uint) (*models.Orde
 	}
 
 	order := &models.Order{
-		UserID:  userID,
-		Total:   total,
-		Status:  "pending",
-		// Keep M2M for quick reads; GORM will maintain join, but we also store richer OrderProduct rows
+		UserID:   &userID,
+		Subtotal: total,
+		Total:    total,
+		Currency: money.BaseCurrency(),
+		Status:   models.OrderStatusPending,
+		// Products — только для ответа; строки order_products пишутся вместе с количеством и ценой.
 		Products: products,
 	}
 
-	if err := s.orderRepo.Create(order); err != nil {
-		return nil, fmt.Errorf("failed to create order: %w", err)
+	address, err := s.resolveAddress(userID, input.AddressID)
+	if err != nil {
+		return nil, err
+	}
+	if address != nil {
+		order.ShippingFullName = address.FullName
+		order.ShippingPhone = address.Phone
+		order.ShippingRegion = address.Region
+		order.ShippingCity = address.City
+		order.ShippingAddress = address.AddressLine
+		order.ShippingPostalCode = address.PostalCode
+	}
+	if input.ShippingMethodID != nil {
+		if address == nil {
+			return nil, fmt.Errorf("shipping address is required")
+		}
+		if err := s.applyShipping(order, *input.ShippingMethodID); err != nil {
+			return nil, err
+		}
 	}
 
-	// Attach order id to items and persist
-	for i := range items {
-		items[i].OrderID = order.ID
+	if err := s.taxService.ApplyTaxes(order, items, products); err != nil {
+		return nil, err
 	}
-	if err := s.orderRepo.CreateOrderProducts(items); err != nil {
-		return nil, fmt.Errorf("failed to save order items: %w", err)
+	order.Total = orderTotal(order)
+
+	if err := s.orderRepo.CreateWithItems(order, items); err != nil {
+		if errors.Is(err, repositories.ErrInsufficientStock) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
 
 
 	notification := &models.Notification{
 		UserID:  userID,
 		Title:   "Заказ оформлен",
-		Message: fmt.Sprintf("Ваш заказ #%d успешно оформлен. Сумма: %.2f руб.", order.ID, total),
+		Message: fmt.Sprintf("Ваш заказ #%d успешно оформлен. Сумма: %s", order.ID, money.New(order.Total, order.Currency)),
 		Type:    "success",
 	}
 	_ = s.notificationRepo.Create(notification) 
@@ -91,15 +161,204 @@ func (s *orderService) CreateOrder(userID uint, productIDs []uint) (*models.Orde
 		_ = s.cartRepo.ClearCart(cart.ID)
 	}
 
+	_ = s.reminderRepo.MarkRecovered(userID, order.ID, time.Now().Add(-cartRecoveryWindow))
+
+	return order, nil
+}
+
+// Гостевой заказ оформляется из гостевой корзины с учётом количества в строках.
+func (s *orderService) CreateGuestOrder(guestToken string, input GuestCheckoutInput) (*models.Order, error) {
+	cart, err := s.cartRepo.FindByGuestToken(guestToken)
+	if err != nil || len(cart.Items) == 0 {
+		return nil, fmt.Errorf("cart is empty")
+	}
+
+	var total money.Amount
+	products := make([]models.Product, 0, len(cart.Items))
+	items := make([]models.OrderProduct, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		p := item.Product
+		if p.ID == 0 || p.Status != models.ProductStatusActive {
+			return nil, fmt.Errorf("product %d is not available", item.ProductID)
+		}
+		if p.Stock < item.Quantity {
+			return nil, fmt.Errorf("%w for %q", repositories.ErrInsufficientStock, p.Name)
+		}
+		total += p.Price.Mul(item.Quantity)
+		products = append(products, p)
+		items = append(items, models.OrderProduct{
+			ProductID: p.ID,
+			Quantity:  item.Quantity,
+			Price:     p.Price,
+		})
+	}
+
+	order := &models.Order{
+		Subtotal:           total,
+		Total:              total,
+		Currency:           money.BaseCurrency(),
+		Status:             models.OrderStatusPending,
+		Products:           products,
+		GuestEmail:         input.Email,
+		GuestName:          input.FullName,
+		GuestPhone:         input.Phone,
+		ShippingFullName:   input.FullName,
+		ShippingPhone:      input.Phone,
+		ShippingCity:       input.City,
+		ShippingAddress:    input.Address,
+		ShippingPostalCode: input.PostalCode,
+	}
+
+	if input.Region != "" {
+		region, err := normalizeRegion(input.Region)
+		if err != nil {
+			return nil, err
+		}
+		order.ShippingRegion = region
+	}
+	if input.ShippingMethodID != nil {
+		if order.ShippingRegion == "" {
+			return nil, fmt.Errorf("region is required for delivery")
+		}
+		if err := s.applyShipping(order, *input.ShippingMethodID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.taxService.ApplyTaxes(order, items, products); err != nil {
+		return nil, err
+	}
+	order.Total = orderTotal(order)
+
+	if err := s.orderRepo.CreateWithItems(order, items); err != nil {
+		if errors.Is(err, repositories.ErrInsufficientStock) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	_ = s.cartRepo.DeleteCart(cart.ID)
+
 	return order, nil
 }
 
+// Без явного адреса используется адрес по умолчанию, если он есть.
+func (s *orderService) resolveAddress(userID uint, addressID *uint) (*models.Address, error) {
+	if addressID == nil {
+		address, err := s.addressRepo.FindDefault(userID)
+		if err != nil {
+			return nil, nil
+		}
+		return address, nil
+	}
+	address, err := s.addressRepo.FindByID(*addressID)
+	if err != nil || address.UserID != userID {
+		return nil, fmt.Errorf("address not found")
+	}
+	return address, nil
+}
+
+// Стоимость доставки фиксируется в заказе, чтобы смена тарифов не меняла старые заказы.
+func (s *orderService) applyShipping(order *models.Order, methodID uint) error {
+	quote, err := s.shippingService.Quote(methodID, order.ShippingRegion, order.ShippingCity, order.Subtotal)
+	if err != nil {
+		return err
+	}
+	order.ShippingMethodID = &quote.MethodID
+	order.ShippingMethodName = quote.Name
+	order.ShippingCost = quote.Price
+	return nil
+}
+
 func (s *orderService) GetUserOrders(userID uint) ([]models.Order, error) {
 	return s.orderRepo.FindByUserID(userID)
 }
 
-func (s *orderService) GetOrderByID(id uint) (*models.Order, error) {
-	return s.orderRepo.FindByID(id)
+func (s *orderService) GetOrderByID(id, userID uint, isAdmin bool) (*models.Order, error) {
+	order, err := s.orderRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("order not found")
+	}
+	if !isAdmin && (order.UserID == nil || *order.UserID != userID) {
+		return nil, fmt.Errorf("order not found")
+	}
+	return order, nil
+}
+
+func (s *orderService) CancelOrder(orderID, userID uint, reason string) (*models.Order, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("cancellation reason is required")
+	}
+
+	order, err := s.GetOrderByID(orderID, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	if !isCancellable(order.Status) {
+		return nil, fmt.Errorf("order in status %q cannot be cancelled", order.Status)
+	}
+
+	// Платёжного шлюза нет: оплаченный заказ помечается к возврату,
+	// неоплаченный — аннулируется.
+	paymentStatus := models.PaymentStatusVoided
+	if order.PaymentStatus == models.PaymentStatusPaid {
+		paymentStatus = models.PaymentStatusRefunded
+	}
+
+	if err := s.orderRepo.Cancel(order.ID, reason, paymentStatus, time.Now()); err != nil {
+		if errors.Is(err, repositories.ErrOrderNotCancelable) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	order, err = s.orderRepo.FindByID(order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	s.notifyCancelled(order)
+	return order, nil
+}
+
+func isCancellable(status string) bool {
+	for _, st := range models.CancellableOrderStatuses {
+		if st == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *orderService) notifyCancelled(order *models.Order) {
+	_ = s.notificationRepo.Create(&models.Notification{
+		UserID:  *order.UserID,
+		Title:   "Заказ отменён",
+		Message: fmt.Sprintf("Ваш заказ #%d отменён.", order.ID),
+		Type:    "info",
+	})
+	if order.User.Email != "" {
+		body := fmt.Sprintf("<p>Ваш заказ #%d отменён.</p>", order.ID)
+		if order.PaymentStatus == models.PaymentStatusRefunded {
+			body += "<p>Средства будут возвращены тем же способом, которым был оплачен заказ.</p>"
+		}
+		if err := utils.SendEmail(order.User.Email, "Заказ отменён", body); err != nil {
+			log.Println("❌ Ошибка при отправке email:", err)
+		}
+	}
+
+	admins, err := s.userRepo.FindByRole("admin")
+	if err != nil {
+		return
+	}
+	for _, admin := range admins {
+		_ = s.notificationRepo.Create(&models.Notification{
+			UserID:  admin.ID,
+			Title:   "Покупатель отменил заказ",
+			Message: fmt.Sprintf("Заказ #%d отменён покупателем. Причина: %s", order.ID, order.CancelReason),
+			Type:    "warning",
+		})
+	}
 }
 
 func (s *orderService) GetAllOrders() ([]models.Order, error) {
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"testing"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
+	"1kosmetika-marketplace-backend/repositories"
+)
+
+type fakeCartRepo struct {
+	repositories.CartRepository
+	cart *models.Cart
+}
+
+func (r *fakeCartRepo) FindByGuestToken(token string) (*models.Cart, error) {
+	return r.cart, nil
+}
+
+func (r *fakeCartRepo) UpdateCartItem(item *models.CartItem) error { return nil }
+
+func (r *fakeCartRepo) DeleteCart(cartID uint) error { return nil }
+
+type fakeTaxRepo struct {
+	repositories.TaxRepository
+	rates []models.TaxRate
+}
+
+func (r *fakeTaxRepo) FindActive() ([]models.TaxRate, error) {
+	return r.rates, nil
+}
+
+type createdOrderRepo struct {
+	repositories.OrderRepository
+	orders []models.Order
+	items  [][]models.OrderProduct
+}
+
+func (r *createdOrderRepo) CreateWithItems(order *models.Order, items []models.OrderProduct) error {
+	r.orders = append(r.orders, *order)
+	r.items = append(r.items, items)
+	return nil
+}
+
+type fixedShipping struct {
+	ShippingService
+	price money.Amount
+}
+
+func (s *fixedShipping) Quote(methodID uint, region, city string, subtotal money.Amount) (*ShippingQuote, error) {
+	return &ShippingQuote{MethodID: methodID, Name: "Courier", Price: s.price}, nil
+}
+
+func amount(t *testing.T, s string) money.Amount {
+	t.Helper()
+	a, err := money.Parse(s)
+	if err != nil {
+		t.Fatalf("money.Parse(%q): %v", s, err)
+	}
+	return a
+}
+
+// Сумма строк корзины = подытог заказа, итог = подытог + доставка (+ налог сверху),
+// и в базу уходит заказ с уже посчитанными итогами.
+func TestCartAndOrderTotalsAgree(t *testing.T) {
+	type line struct {
+		price    string
+		qty      int
+		category string
+	}
+	rates := []models.TaxRate{
+		{Name: "VAT", Rate: 15, IsActive: true},
+		{Name: "Cosmetics", Rate: 12.5, Category: "skincare", IsActive: true},
+	}
+
+	tests := []struct {
+		name         string
+		lines        []line
+		rates        []models.TaxRate
+		inclusive    bool
+		shipping     string
+		wantSubtotal string
+		wantTax      string
+		wantTotal    string
+	}{
+		{
+			name:         "tenths add up exactly",
+			lines:        []line{{"0.10", 1, ""}, {"0.20", 1, ""}},
+			wantSubtotal: "0.30",
+			wantTax:      "0.00",
+			wantTotal:    "0.30",
+		},
+		{
+			name:         "tax on top is rounded per line",
+			lines:        []line{{"3.33", 3, ""}, {"0.07", 1, ""}},
+			rates:        rates,
+			shipping:     "5.00",
+			wantSubtotal: "10.06",
+			wantTax:      "1.51", // 9.99 × 15% = 1.4985 → 1.50; 0.07 × 15% = 0.0105 → 0.01
+			wantTotal:    "16.57",
+		},
+		{
+			name:         "category rate beats the default one",
+			lines:        []line{{"19.99", 2, "skincare"}, {"0.01", 1, "makeup"}},
+			rates:        rates,
+			shipping:     "0.99",
+			wantSubtotal: "39.99",
+			wantTax:      "5.00", // 39.98 × 12.5% = 4.9975 → 5.00; 0.01 × 15% → 0.00
+			wantTotal:    "45.98",
+		},
+		{
+			name:         "tax included in prices is not added again",
+			lines:        []line{{"11.50", 1, ""}, {"0.99", 3, "skincare"}},
+			rates:        rates,
+			inclusive:    true,
+			shipping:     "2.50",
+			wantSubtotal: "14.47",
+			wantTax:      "1.83", // 11.50 × 15/115 = 1.50; 2.97 × 12.5/112.5 = 0.33
+			wantTotal:    "16.97",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cart := &models.Cart{ID: 1, GuestToken: "guest"}
+			for i, l := range tt.lines {
+				id := uint(i + 1)
+				price := amount(t, l.price)
+				cart.Items = append(cart.Items, models.CartItem{
+					ID:        id,
+					ProductID: id,
+					Quantity:  l.qty,
+					UnitPrice: price,
+					Price:     price.Mul(l.qty),
+					Product: models.Product{
+						ID:       id,
+						Name:     l.price,
+						Price:    price,
+						Stock:    100,
+						Status:   models.ProductStatusActive,
+						Category: l.category,
+					},
+				})
+			}
+
+			cartRepo := &fakeCartRepo{cart: cart}
+			orders := &createdOrderRepo{}
+			carts := &cartService{cartRepo: cartRepo}
+			checkout := &orderService{
+				orderRepo:       orders,
+				cartRepo:        cartRepo,
+				shippingService: &fixedShipping{price: amount(t, orDefault(tt.shipping))},
+				taxService:      NewTaxService(&fakeTaxRepo{rates: tt.rates}, nil, tt.inclusive),
+			}
+
+			summary, err := carts.GetGuestCart("guest")
+			if err != nil {
+				t.Fatalf("GetGuestCart: %v", err)
+			}
+			var lines []money.Amount
+			for _, item := range summary.Items {
+				lines = append(lines, item.Price)
+			}
+			if got := money.Sum(lines...); got != summary.Subtotal {
+				t.Fatalf("cart lines sum %s != cart subtotal %s", got, summary.Subtotal)
+			}
+
+			input := GuestCheckoutInput{Email: "guest@example.com", FullName: "Guest", Region: "ashgabat"}
+			if tt.shipping != "" {
+				methodID := uint(1)
+				input.ShippingMethodID = &methodID
+			}
+			order, err := checkout.CreateGuestOrder("guest", input)
+			if err != nil {
+				t.Fatalf("CreateGuestOrder: %v", err)
+			}
+
+			if order.Subtotal != summary.Subtotal {
+				t.Errorf("order subtotal %s != cart subtotal %s", order.Subtotal, summary.Subtotal)
+			}
+			if want := amount(t, tt.wantSubtotal); order.Subtotal != want {
+				t.Errorf("subtotal = %s, want %s", order.Subtotal, want)
+			}
+			if want := amount(t, tt.wantTax); order.TaxTotal != want {
+				t.Errorf("tax = %s, want %s", order.TaxTotal, want)
+			}
+			if want := amount(t, tt.wantTotal); order.Total != want {
+				t.Errorf("total = %s, want %s", order.Total, want)
+			}
+			if order.Total != orderTotal(order) {
+				t.Errorf("total %s != orderTotal %s", order.Total, orderTotal(order))
+			}
+
+			var taxes, gross, net []money.Amount
+			for _, item := range orders.items[0] {
+				taxes = append(taxes, item.TaxAmount)
+				gross = append(gross, item.Price.Mul(item.Quantity))
+				net = append(net, item.NetAmount)
+			}
+			if got := money.Sum(taxes...); got != order.TaxTotal {
+				t.Errorf("line taxes sum %s != order tax %s", got, order.TaxTotal)
+			}
+			if got := money.Sum(gross...); got != order.Subtotal {
+				t.Errorf("order lines sum %s != order subtotal %s", got, order.Subtotal)
+			}
+			if tt.inclusive {
+				if got := money.Sum(net...) + order.TaxTotal; got != order.Subtotal {
+					t.Errorf("net + tax = %s, want subtotal %s", got, order.Subtotal)
+				}
+			}
+
+			saved := orders.orders[0]
+			if saved.Subtotal != order.Subtotal || saved.ShippingCost != order.ShippingCost ||
+				saved.TaxTotal != order.TaxTotal || saved.Total != order.Total {
+				t.Errorf("saved order totals %s/%s/%s/%s, want %s/%s/%s/%s",
+					saved.Subtotal, saved.ShippingCost, saved.TaxTotal, saved.Total,
+					order.Subtotal, order.ShippingCost, order.TaxTotal, order.Total)
+			}
+		})
+	}
+}
+
+func orDefault(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
@@ -2,8 +2,16 @@ package services
 
 import (
 	"fmt"
+	"log"
+	"strings"
+	"time"
+
 	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
 	"1kosmetika-marketplace-backend/repositories"
+	"1kosmetika-marketplace-backend/utils"
+
+	"github.com/google/uuid"
 )
 
 type ProductService interface {
@@ -19,24 +27,93 @@ type ProductService interface {
 	GetCategories() ([]string, error)
 	GetBrands() ([]string, error)
 	ValidateProduct(product *models.Product) error
+	GetProductBySlug(slug string) (*models.Product, bool, error)
+	GetProductForAdmin(id uint) (*models.Product, error)
+	GetProductsForAdmin(filter repositories.ProductFilter, page, limit int) ([]models.Product, int64, error)
+	PublishScheduledProducts(now time.Time) error
+	GetPriceHistory(productID uint) ([]models.PriceHistory, error)
+	ApplyScheduledPrices(now time.Time) error
+	// Restock увеличивает остаток на складе; подписчики на поступление
+	// получают уведомления из планировщика.
+	Restock(id uint, quantity int) (*models.Product, error)
 }
 
 type productService struct {
-	productRepo repositories.ProductRepository
+	productRepo      repositories.ProductRepository
+	cartRepo         repositories.CartRepository
+	favoriteRepo     repositories.FavoriteRepository
+	notificationRepo repositories.NotificationRepository
+	priceHistoryRepo repositories.PriceHistoryRepository
 }
 
-func NewProductService(productRepo repositories.ProductRepository) ProductService {
-	return &productService{productRepo: productRepo}
+func NewProductService(
+	productRepo repositories.ProductRepository,
+	cartRepo repositories.CartRepository,
+	favoriteRepo repositories.FavoriteRepository,
+	notificationRepo repositories.NotificationRepository,
+	priceHistoryRepo repositories.PriceHistoryRepository,
+) ProductService {
+	return &productService{
+		productRepo:      productRepo,
+		cartRepo:         cartRepo,
+		favoriteRepo:     favoriteRepo,
+		notificationRepo: notificationRepo,
+		priceHistoryRepo: priceHistoryRepo,
+	}
 }
 
 func (s *productService) CreateProduct(product *models.Product) error {
+	// Акция включается только планировщиком, рейтинг — отзывами,
+	// состав — через IngredientService вместе со связями на ингредиенты.
+	product.SaleActive = false
+	product.RatingAvg = 0
+	product.RatingCount = 0
+	product.Ingredients = ""
+
+	if product.Status == "" {
+		product.Status = models.ProductStatusActive
+		if product.PublishAt != nil && product.PublishAt.After(time.Now()) {
+			product.Status = models.ProductStatusDraft
+		}
+	}
+
 	if err := s.ValidateProduct(product); err != nil {
 		return err
 	}
+	if err := normalizeSuitability(product); err != nil {
+		return err
+	}
+
+	slugSource := product.Slug
+	if slugSource == "" {
+		slugSource = product.Name
+	}
+	slug, err := s.uniqueSlug(slugSource, 0)
+	if err != nil {
+		return err
+	}
+	product.Slug = slug
+
+	if err := s.assignSKU(product, 0); err != nil {
+		return err
+	}
+
 	return s.productRepo.Create(product)
 }
 
+// Публичная карточка товара: черновики и архив не показываются.
 func (s *productService) GetProductByID(id uint) (*models.Product, error) {
+	product, err := s.productRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if product.Status != models.ProductStatusActive {
+		return nil, fmt.Errorf("product not found")
+	}
+	return product, nil
+}
+
+func (s *productService) GetProductForAdmin(id uint) (*models.Product, error) {
 	return s.productRepo.FindByID(id)
 }
 
@@ -45,29 +122,149 @@ func (s *productService) GetAllProducts() ([]models.Product, error) {
 }
 
 func (s *productService) UpdateProduct(id uint, product *models.Product) error {
+	existingProduct, err := s.productRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if product.Status == "" {
+		product.Status = existingProduct.Status
+	}
+	product.SaleActive = existingProduct.SaleActive
 	if err := s.ValidateProduct(product); err != nil {
 		return err
 	}
-	
-	existingProduct, err := s.productRepo.FindByID(id)
-	if err != nil {
+	if err := normalizeSuitability(product); err != nil {
 		return err
 	}
 
+	oldName := existingProduct.Name
+	oldPrice := existingProduct.Price
+
 	// Update fields
 	existingProduct.Name = product.Name
 	existingProduct.Description = product.Description
-	existingProduct.Price = product.Price
 	existingProduct.ImageURL = product.ImageURL
 	existingProduct.Category = product.Category
 	existingProduct.Brand = product.Brand
 	existingProduct.Stock = product.Stock
+	existingProduct.MetaTitle = product.MetaTitle
+	existingProduct.MetaDescription = product.MetaDescription
+	existingProduct.Status = product.Status
+	existingProduct.PublishAt = product.PublishAt
+	existingProduct.UnpublishAt = product.UnpublishAt
+	existingProduct.SaleStartsAt = product.SaleStartsAt
+	existingProduct.SaleEndsAt = product.SaleEndsAt
+	existingProduct.SkinTypes = product.SkinTypes
+	existingProduct.HairTypes = product.HairTypes
+	existingProduct.Concerns = product.Concerns
+	priceReason := models.PriceChangeManual
+	if existingProduct.SaleActive {
+		// Во время акции Price — цена акции, а обычная цена хранится в
+		// CompareAtPrice: правка цены меняет обычную цену, которая вернётся
+		// по окончании акции.
+		regularPrice := existingProduct.CompareAtPrice
+		if product.Price != existingProduct.Price {
+			regularPrice = product.Price
+		}
+		if product.SalePrice == 0 {
+			// Снятая цена акции завершает акцию сразу.
+			existingProduct.Price = regularPrice
+			existingProduct.CompareAtPrice = 0
+			existingProduct.SaleStartsAt = nil
+			existingProduct.SaleEndsAt = nil
+			existingProduct.SaleActive = false
+			priceReason = models.PriceChangeSaleEnd
+		} else {
+			if product.SalePrice >= regularPrice {
+				return fmt.Errorf("sale price must be lower than the regular price")
+			}
+			existingProduct.Price = product.SalePrice
+			existingProduct.CompareAtPrice = regularPrice
+		}
+	} else {
+		existingProduct.Price = product.Price
+		existingProduct.CompareAtPrice = product.CompareAtPrice
+	}
+	existingProduct.SalePrice = product.SalePrice
+
+	if product.SKU != "" && product.SKU != existingProduct.SKU {
+		existingProduct.SKU = product.SKU
+		if err := s.assignSKU(existingProduct, id); err != nil {
+			return err
+		}
+	}
+
+	// Явно переданный slug имеет приоритет; иначе slug пересчитывается при переименовании.
+	oldSlug := existingProduct.Slug
+	newSlug := oldSlug
+	if product.Slug != "" {
+		newSlug = utils.Slugify(product.Slug)
+	} else if oldSlug == "" || utils.Slugify(oldName) != utils.Slugify(product.Name) {
+		newSlug = utils.Slugify(product.Name)
+	}
+	if newSlug != oldSlug {
+		slug, err := s.uniqueSlug(newSlug, id)
+		if err != nil {
+			return err
+		}
+		existingProduct.Slug = slug
+	}
+
+	if err := s.productRepo.Update(existingProduct); err != nil {
+		return err
+	}
+
+	if existingProduct.Price != oldPrice {
+		s.recordPriceChange(id, oldPrice, existingProduct.Price, priceReason)
+		if priceReason == models.PriceChangeSaleEnd {
+			s.repriceCarts(existingProduct, oldPrice)
+		}
+	}
+
+	if existingProduct.Slug != oldSlug {
+		_ = s.productRepo.DeleteSlugRedirect(existingProduct.Slug)
+		if oldSlug != "" {
+			if err := s.productRepo.SaveSlugRedirect(oldSlug, id); err != nil {
+				return fmt.Errorf("failed to save slug redirect: %w", err)
+			}
+		}
+	}
 
-	return s.productRepo.Update(existingProduct)
+	*product = *existingProduct
+	return nil
 }
 
+// Мягкое удаление: строка остаётся для истории заказов и отзывов,
+// товар убирается из корзин, а пользователи из избранного получают уведомление.
 func (s *productService) DeleteProduct(id uint) error {
-	return s.productRepo.Delete(id)
+	product, err := s.productRepo.FindByID(id)
+	if err != nil {
+		return fmt.Errorf("product not found")
+	}
+
+	if err := s.productRepo.Delete(id); err != nil {
+		return err
+	}
+
+	if _, err := s.cartRepo.DeleteItemsByProduct(id); err != nil {
+		log.Printf("❌ Failed to remove product %d from carts: %v", id, err)
+	}
+
+	userIDs, err := s.favoriteRepo.FindUserIDsByProduct(id)
+	if err != nil {
+		log.Printf("❌ Failed to load favorites for product %d: %v", id, err)
+		return nil
+	}
+	for _, userID := range userIDs {
+		_ = s.notificationRepo.Create(&models.Notification{
+			UserID:  userID,
+			Title:   "Товар больше недоступен",
+			Message: fmt.Sprintf("Товар «%s» из вашего избранного больше не продаётся.", product.Name),
+			Type:    "warning",
+		})
+	}
+	return nil
 }
 
 func (s *productService) GetProductsByIDs(ids []uint) ([]models.Product, error) {
@@ -98,6 +295,13 @@ func (s *productService) GetProductsWithFilters(filter repositories.ProductFilte
 	return s.productRepo.FindWithFilters(filter, page, limit)
 }
 
+func (s *productService) GetProductsForAdmin(filter repositories.ProductFilter, page, limit int) ([]models.Product, int64, error) {
+	if filter.Status == "" {
+		filter.Status = "all"
+	}
+	return s.GetProductsWithFilters(filter, page, limit)
+}
+
 func (s *productService) GetCategories() ([]string, error) {
 	return s.productRepo.GetCategories()
 }
@@ -106,6 +310,19 @@ func (s *productService) GetBrands() ([]string, error) {
 	return s.productRepo.GetBrands()
 }
 
+// normalizeSuitability проверяет атрибуты подбора и убирает повторы.
+func normalizeSuitability(product *models.Product) error {
+	var err error
+	if product.SkinTypes, err = normalizeBeautyList("skin_types", product.SkinTypes, models.SkinTypes); err != nil {
+		return err
+	}
+	if product.HairTypes, err = normalizeBeautyList("hair_types", product.HairTypes, models.HairTypes); err != nil {
+		return err
+	}
+	product.Concerns, err = normalizeBeautyList("concerns", product.Concerns, models.BeautyConcerns)
+	return err
+}
+
 func (s *productService) ValidateProduct(product *models.Product) error {
 	if product.Name == "" {
 		return fmt.Errorf("product name is required")
@@ -125,5 +342,216 @@ func (s *productService) ValidateProduct(product *models.Product) error {
 	if product.Stock < 0 {
 		return fmt.Errorf("product stock cannot be negative")
 	}
+	switch product.Status {
+	case models.ProductStatusDraft, models.ProductStatusActive, models.ProductStatusArchived:
+	default:
+		return fmt.Errorf("product status must be draft, active or archived")
+	}
+	if product.PublishAt != nil && product.UnpublishAt != nil && !product.UnpublishAt.After(*product.PublishAt) {
+		return fmt.Errorf("unpublish_at must be after publish_at")
+	}
+	if product.SalePrice < 0 || product.CompareAtPrice < 0 {
+		return fmt.Errorf("sale and compare-at prices cannot be negative")
+	}
+	if product.SalePrice > 0 {
+		if product.SaleStartsAt == nil {
+			return fmt.Errorf("sale_starts_at is required for a sale price")
+		}
+		if product.SaleEndsAt != nil && !product.SaleEndsAt.After(*product.SaleStartsAt) {
+			return fmt.Errorf("sale_ends_at must be after sale_starts_at")
+		}
+		if !product.SaleActive && product.SalePrice >= product.Price {
+			return fmt.Errorf("sale price must be lower than the regular price")
+		}
+	}
+	return nil
+}
+
+func (s *productService) PublishScheduledProducts(now time.Time) error {
+	published, err := s.productRepo.PublishScheduled(now)
+	if err != nil {
+		return fmt.Errorf("failed to publish products: %w", err)
+	}
+	unpublished, err := s.productRepo.UnpublishScheduled(now)
+	if err != nil {
+		return fmt.Errorf("failed to unpublish products: %w", err)
+	}
+	if published > 0 || unpublished > 0 {
+		log.Printf("🗓️ Products published: %d, archived: %d", published, unpublished)
+	}
+	return nil
+}
+
+// Второй результат — true, если товар найден по старому slug'у и клиенту нужен редирект.
+func (s *productService) GetProductBySlug(slug string) (*models.Product, bool, error) {
+	product, err := s.productRepo.FindBySlug(slug)
+	if err == nil {
+		if product.Status != models.ProductStatusActive {
+			return nil, false, fmt.Errorf("product not found")
+		}
+		return product, false, nil
+	}
+
+	redirect, err := s.productRepo.FindSlugRedirect(slug)
+	if err != nil {
+		return nil, false, fmt.Errorf("product not found")
+	}
+	product, err = s.productRepo.FindByID(redirect.ProductID)
+	if err != nil || product.Status != models.ProductStatusActive {
+		return nil, false, fmt.Errorf("product not found")
+	}
+	return product, true, nil
+}
+
+func (s *productService) uniqueSlug(source string, excludeID uint) (string, error) {
+	base := utils.Slugify(source)
+	if base == "" {
+		base = "product"
+	}
+
+	slug := base
+	for i := 2; i <= 100; i++ {
+		exists, err := s.productRepo.SlugExists(slug, excludeID)
+		if err != nil {
+			return "", fmt.Errorf("failed to check slug: %w", err)
+		}
+		if !exists {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+	return base + "-" + uuid.NewString()[:8], nil
+}
+
+// Переданный SKU проверяется на уникальность, пустой — генерируется.
+func (s *productService) assignSKU(product *models.Product, excludeID uint) error {
+	product.SKU = strings.ToUpper(strings.TrimSpace(product.SKU))
+	if product.SKU != "" {
+		exists, err := s.productRepo.SKUExists(product.SKU, excludeID)
+		if err != nil {
+			return fmt.Errorf("failed to check sku: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("product with this sku already exists")
+		}
+		return nil
+	}
+
+	for {
+		sku := "KOS-" + strings.ToUpper(uuid.NewString()[:8])
+		exists, err := s.productRepo.SKUExists(sku, excludeID)
+		if err != nil {
+			return fmt.Errorf("failed to check sku: %w", err)
+		}
+		if !exists {
+			product.SKU = sku
+			return nil
+		}
+	}
+}
+
+func (s *productService) GetPriceHistory(productID uint) ([]models.PriceHistory, error) {
+	if _, err := s.GetProductByID(productID); err != nil {
+		return nil, fmt.Errorf("product not found")
+	}
+	return s.priceHistoryRepo.FindByProductID(productID)
+}
+
+// ApplyScheduledPrices запускает и завершает запланированные акции.
+// Во время акции обычная цена хранится в CompareAtPrice и показывается зачёркнутой.
+func (s *productService) ApplyScheduledPrices(now time.Time) error {
+	toStart, err := s.productRepo.FindSalesToStart(now)
+	if err != nil {
+		return fmt.Errorf("failed to load sales to start: %w", err)
+	}
+	for i := range toStart {
+		p := &toStart[i]
+		oldPrice := p.Price
+		p.CompareAtPrice = p.Price
+		p.Price = p.SalePrice
+		p.SaleActive = true
+		if err := s.productRepo.Update(p); err != nil {
+			log.Printf("❌ Failed to start sale for product %d: %v", p.ID, err)
+			continue
+		}
+		s.recordPriceChange(p.ID, oldPrice, p.Price, models.PriceChangeSaleStart)
+		s.repriceCarts(p, oldPrice)
+	}
+
+	toEnd, err := s.productRepo.FindSalesToEnd(now)
+	if err != nil {
+		return fmt.Errorf("failed to load sales to end: %w", err)
+	}
+	for i := range toEnd {
+		p := &toEnd[i]
+		oldPrice := p.Price
+		if p.CompareAtPrice > 0 {
+			p.Price = p.CompareAtPrice
+		}
+		p.CompareAtPrice = 0
+		p.SalePrice = 0
+		p.SaleStartsAt = nil
+		p.SaleEndsAt = nil
+		p.SaleActive = false
+		if err := s.productRepo.Update(p); err != nil {
+			log.Printf("❌ Failed to end sale for product %d: %v", p.ID, err)
+			continue
+		}
+		if p.Price != oldPrice {
+			s.recordPriceChange(p.ID, oldPrice, p.Price, models.PriceChangeSaleEnd)
+			s.repriceCarts(p, oldPrice)
+		}
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+func (s *productService) recordPriceChange(productID uint, oldPrice, newPrice money.Amount, reason string) {
+	entry := &models.PriceHistory{
+		ProductID: productID,
+		OldPrice:  oldPrice,
+		NewPrice:  newPrice,
+		Reason:    reason,
+	}
+	if err := s.priceHistoryRepo.Create(entry); err != nil {
+		log.Printf("❌ Failed to record price change for product %d: %v", productID, err)
+	}
+}
+
+// Пересчитывает строки корзин с товаром и сообщает владельцам, какая позиция изменилась.
+func (s *productService) repriceCarts(product *models.Product, oldPrice money.Amount) {
+	userIDs, err := s.cartRepo.FindUserIDsByProduct(product.ID)
+	if err != nil {
+		log.Printf("❌ Failed to find carts for product %d: %v", product.ID, err)
+		return
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+	if err := s.cartRepo.RepriceProduct(product.ID, product.Price); err != nil {
+		log.Printf("❌ Failed to reprice carts for product %d: %v", product.ID, err)
+		return
+	}
+
+	title := "Цена товара в корзине снизилась"
+	if product.Price > oldPrice {
+		title = "Цена товара в корзине изменилась"
+	}
+	for _, userID := range userIDs {
+		_ = s.notificationRepo.Create(&models.Notification{
+			UserID:  userID,
+			Title:   title,
+			Message: fmt.Sprintf("«%s»: %s → %s", product.Name, money.Format(oldPrice), money.Format(product.Price)),
+			Type:    "info",
+		})
+	}
+}
+
+func (s *productService) Restock(id uint, quantity int) (*models.Product, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("quantity must be positive")
+	}
+	if err := s.productRepo.IncrementStock(id, quantity); err != nil {
+		return nil, fmt.Errorf("product not found")
+	}
+	return s.productRepo.FindByID(id)
+}
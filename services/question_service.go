@@ -0,0 +1,214 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/repositories"
+)
+
+// ProductQuestions — страница вопросов о товаре.
+type ProductQuestions struct {
+	Questions []models.ProductQuestion `json:"questions"`
+	Total     int64                    `json:"total"`
+	Page      int                      `json:"page"`
+	Limit     int                      `json:"limit"`
+	Pages     int64                    `json:"pages"`
+}
+
+type QuestionService interface {
+	AskQuestion(userID, productID uint, body string) (*models.ProductQuestion, error)
+	GetProductQuestions(productID uint, search string, page, limit int) (*ProductQuestions, error)
+	// AnswerQuestion — отвечать могут администраторы и покупатели товара.
+	AnswerQuestion(questionID, userID uint, isAdmin bool, body string) (*models.ProductAnswer, error)
+	UpvoteAnswer(answerID, userID uint) (*models.ProductAnswer, error)
+	RemoveUpvote(answerID, userID uint) (*models.ProductAnswer, error)
+	GetModerationQueue(status string, page, limit int) ([]models.ProductQuestion, int64, error)
+	ModerateQuestion(questionID uint, status string) (*models.ProductQuestion, error)
+	DeleteQuestion(questionID uint) error
+}
+
+type questionService struct {
+	questionRepo     repositories.QuestionRepository
+	productRepo      repositories.ProductRepository
+	orderRepo        repositories.OrderRepository
+	notificationRepo repositories.NotificationRepository
+}
+
+func NewQuestionService(
+	questionRepo repositories.QuestionRepository,
+	productRepo repositories.ProductRepository,
+	orderRepo repositories.OrderRepository,
+	notificationRepo repositories.NotificationRepository,
+) QuestionService {
+	return &questionService{
+		questionRepo:     questionRepo,
+		productRepo:      productRepo,
+		orderRepo:        orderRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *questionService) AskQuestion(userID, productID uint, body string) (*models.ProductQuestion, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, fmt.Errorf("question text is required")
+	}
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil || product.Status != models.ProductStatusActive {
+		return nil, fmt.Errorf("product not found")
+	}
+
+	question := &models.ProductQuestion{
+		ProductID: productID,
+		UserID:    userID,
+		Body:      body,
+		Status:    models.QuestionStatusPending,
+	}
+	if err := s.questionRepo.CreateQuestion(question); err != nil {
+		return nil, fmt.Errorf("failed to create question: %w", err)
+	}
+	return s.questionRepo.FindQuestionByID(question.ID)
+}
+
+func (s *questionService) GetProductQuestions(productID uint, search string, page, limit int) (*ProductQuestions, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	questions, total, err := s.questionRepo.FindByProduct(productID, strings.TrimSpace(search), page, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &ProductQuestions{
+		Questions: questions,
+		Total:     total,
+		Page:      page,
+		Limit:     limit,
+		Pages:     (total + int64(limit) - 1) / int64(limit),
+	}, nil
+}
+
+func (s *questionService) AnswerQuestion(questionID, userID uint, isAdmin bool, body string) (*models.ProductAnswer, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, fmt.Errorf("answer text is required")
+	}
+	question, err := s.questionRepo.FindQuestionByID(questionID)
+	if err != nil || question.Status != models.QuestionStatusApproved {
+		return nil, fmt.Errorf("question not found")
+	}
+
+	if !isAdmin {
+		bought, err := s.orderRepo.HasCompletedPurchase(userID, question.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check purchase: %w", err)
+		}
+		if !bought {
+			return nil, fmt.Errorf("only customers who bought this product can answer")
+		}
+	}
+
+	answer := &models.ProductAnswer{
+		QuestionID: questionID,
+		UserID:     userID,
+		Body:       body,
+		IsOfficial: isAdmin,
+	}
+	if err := s.questionRepo.CreateAnswer(answer); err != nil {
+		return nil, fmt.Errorf("failed to save answer: %w", err)
+	}
+
+	if question.UserID != userID {
+		productName := ""
+		if product, err := s.productRepo.FindByID(question.ProductID); err == nil {
+			productName = product.Name
+		}
+		_ = s.notificationRepo.Create(&models.Notification{
+			UserID:  question.UserID,
+			Title:   "На ваш вопрос ответили",
+			Message: fmt.Sprintf("На ваш вопрос о товаре «%s» пришёл ответ: %s", productName, body),
+			Type:    "info",
+		})
+	}
+	return s.questionRepo.FindAnswerByID(answer.ID)
+}
+
+// Голосовать можно только за чужие ответы на опубликованные вопросы.
+func (s *questionService) UpvoteAnswer(answerID, userID uint) (*models.ProductAnswer, error) {
+	answer, err := s.questionRepo.FindAnswerByID(answerID)
+	if err != nil {
+		return nil, fmt.Errorf("answer not found")
+	}
+	question, err := s.questionRepo.FindQuestionByID(answer.QuestionID)
+	if err != nil || question.Status != models.QuestionStatusApproved {
+		return nil, fmt.Errorf("answer not found")
+	}
+	if answer.UserID == userID {
+		return nil, fmt.Errorf("you cannot upvote your own answer")
+	}
+
+	if _, err := s.questionRepo.AddAnswerVote(answerID, userID); err != nil {
+		return nil, fmt.Errorf("failed to save vote: %w", err)
+	}
+	return s.questionRepo.FindAnswerByID(answerID)
+}
+
+func (s *questionService) RemoveUpvote(answerID, userID uint) (*models.ProductAnswer, error) {
+	removed, err := s.questionRepo.RemoveAnswerVote(answerID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove vote: %w", err)
+	}
+	if !removed {
+		return nil, fmt.Errorf("vote not found")
+	}
+	return s.questionRepo.FindAnswerByID(answerID)
+}
+
+func (s *questionService) GetModerationQueue(status string, page, limit int) ([]models.ProductQuestion, int64, error) {
+	if status == "" {
+		status = models.QuestionStatusPending
+	}
+	if !isQuestionStatus(status) {
+		return nil, 0, fmt.Errorf("invalid question status")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.questionRepo.FindByStatus(status, page, limit)
+}
+
+func (s *questionService) ModerateQuestion(questionID uint, status string) (*models.ProductQuestion, error) {
+	if status != models.QuestionStatusApproved && status != models.QuestionStatusRejected {
+		return nil, fmt.Errorf("status must be approved or rejected")
+	}
+	if _, err := s.questionRepo.FindQuestionByID(questionID); err != nil {
+		return nil, fmt.Errorf("question not found")
+	}
+	if err := s.questionRepo.UpdateQuestionStatus(questionID, status); err != nil {
+		return nil, fmt.Errorf("failed to moderate question: %w", err)
+	}
+	return s.questionRepo.FindQuestionByID(questionID)
+}
+
+func (s *questionService) DeleteQuestion(questionID uint) error {
+	if _, err := s.questionRepo.FindQuestionByID(questionID); err != nil {
+		return fmt.Errorf("question not found")
+	}
+	return s.questionRepo.DeleteQuestion(questionID)
+}
+
+func isQuestionStatus(status string) bool {
+	switch status {
+	case models.QuestionStatusPending, models.QuestionStatusApproved, models.QuestionStatusRejected:
+		return true
+	}
+	return false
+}
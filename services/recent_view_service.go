@@ -0,0 +1,59 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/repositories"
+)
+
+// Столько последних просмотров хранится на пользователя.
+const recentViewsLimit = 30
+
+type RecentViewService interface {
+	// RecordView считает просмотр товара; для авторизованного пользователя
+	// (userID != 0) товар попадает ещё и в «недавно просмотренные».
+	RecordView(productID, userID uint)
+	GetRecentlyViewed(userID uint, limit int) ([]models.Product, error)
+	ClearRecentlyViewed(userID uint) error
+}
+
+type recentViewService struct {
+	recentViewRepo repositories.RecentViewRepository
+	productRepo    repositories.ProductRepository
+}
+
+func NewRecentViewService(
+	recentViewRepo repositories.RecentViewRepository,
+	productRepo repositories.ProductRepository,
+) RecentViewService {
+	return &recentViewService{
+		recentViewRepo: recentViewRepo,
+		productRepo:    productRepo,
+	}
+}
+
+// Ошибки учёта только логируются: карточка товара отдаётся в любом случае.
+func (s *recentViewService) RecordView(productID, userID uint) {
+	if err := s.productRepo.IncrementViewCount(productID); err != nil {
+		log.Printf("❌ Failed to count view of product %d: %v", productID, err)
+	}
+	if userID == 0 {
+		return
+	}
+	if err := s.recentViewRepo.Record(userID, productID, time.Now(), recentViewsLimit); err != nil {
+		log.Printf("❌ Failed to record recent view of product %d for user %d: %v", productID, userID, err)
+	}
+}
+
+func (s *recentViewService) GetRecentlyViewed(userID uint, limit int) ([]models.Product, error) {
+	if limit < 1 || limit > recentViewsLimit {
+		limit = recentViewsLimit
+	}
+	return s.recentViewRepo.FindProductsByUser(userID, limit)
+}
+
+func (s *recentViewService) ClearRecentlyViewed(userID uint) error {
+	return s.recentViewRepo.DeleteByUser(userID)
+}
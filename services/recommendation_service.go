@@ -0,0 +1,88 @@
+package services
+
+import (
+	"fmt"
+	"log"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/repositories"
+)
+
+// Сколько рекомендаций хранится на товар и на пользователя; запросы
+// отдают не больше этого.
+const (
+	relatedPerProduct      = 20
+	recommendationsPerUser = 50
+)
+
+type RecommendationService interface {
+	// GetRelated — «с этим товаром покупают»; для товаров без расчёта —
+	// товары той же категории или бренда.
+	GetRelated(productID uint, limit int) ([]models.Product, error)
+	// GetForUser — персональные рекомендации; без истории — лучшие по рейтингу.
+	GetForUser(userID uint, limit int) ([]models.Product, error)
+	// Rebuild пересчитывает рекомендации по заказам и избранному.
+	Rebuild() error
+}
+
+type recommendationService struct {
+	recommendationRepo repositories.RecommendationRepository
+	productRepo        repositories.ProductRepository
+}
+
+func NewRecommendationService(
+	recommendationRepo repositories.RecommendationRepository,
+	productRepo repositories.ProductRepository,
+) RecommendationService {
+	return &recommendationService{
+		recommendationRepo: recommendationRepo,
+		productRepo:        productRepo,
+	}
+}
+
+func (s *recommendationService) GetRelated(productID uint, limit int) ([]models.Product, error) {
+	if limit < 1 || limit > relatedPerProduct {
+		limit = 8
+	}
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil || product.Status != models.ProductStatusActive {
+		return nil, fmt.Errorf("product not found")
+	}
+
+	products, err := s.recommendationRepo.FindRelated(productID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return s.recommendationRepo.FindSimilar(product, limit)
+	}
+	return products, nil
+}
+
+func (s *recommendationService) GetForUser(userID uint, limit int) ([]models.Product, error) {
+	if limit < 1 || limit > recommendationsPerUser {
+		limit = 12
+	}
+
+	products, err := s.recommendationRepo.FindForUser(userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return s.recommendationRepo.FindTopRated(limit)
+	}
+	return products, nil
+}
+
+func (s *recommendationService) Rebuild() error {
+	related, err := s.recommendationRepo.RebuildRelated(relatedPerProduct)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild related products: %w", err)
+	}
+	personal, err := s.recommendationRepo.RebuildUserRecommendations(recommendationsPerUser)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild user recommendations: %w", err)
+	}
+	log.Printf("🧭 Recommendations rebuilt: %d related, %d personal", related, personal)
+	return nil
+}
@@ -1,33 +1,82 @@
 package services
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"strings"
+	"time"
+
 	"1kosmetika-marketplace-backend/models"
 	"1kosmetika-marketplace-backend/repositories"
 )
 
+const (
+	maxReviewPhotos = 5
+	// Фото отзывов принимаются только из собственного хранилища загрузок.
+	reviewPhotoURLPrefix = "/static/reviews/"
+)
+
+// ProductReviews — страница отзывов товара со сводкой по всем опубликованным.
+type ProductReviews struct {
+	Reviews    []models.Review `json:"reviews"`
+	Total      int64           `json:"total"` // с учётом фильтров
+	Page       int             `json:"page"`
+	Limit      int             `json:"limit"`
+	Pages      int64           `json:"pages"`
+	AvgRating  float64         `json:"avg_rating"`
+	TotalCount int             `json:"total_count"`
+	Histogram  map[int]int64   `json:"histogram"`
+}
+
 type ReviewService interface {
-	CreateReview(userID uint, productID uint, rating int, comment string) (*models.Review, error)
-	UpdateReview(reviewID uint, userID uint, rating int, comment string) (*models.Review, error)
+	CreateReview(userID uint, productID uint, rating int, comment string, photos []string) (*models.Review, error)
+	// UpdateReview снова отправляет отзыв на модерацию; photos == nil — фото не меняются.
+	UpdateReview(reviewID uint, userID uint, rating int, comment string, photos []string) (*models.Review, error)
 	DeleteReview(reviewID uint, userID uint) error
-	GetProductReviews(productID uint) ([]models.Review, float64, int, error)
+	GetProductReviews(productID uint, query repositories.ReviewQuery) (*ProductReviews, error)
 	GetUserReviews(userID uint) ([]models.Review, error)
 	GetReviewByID(reviewID uint) (*models.Review, error)
+	GetModerationQueue(status string, page, limit int) ([]models.Review, int64, error)
+	ModerateReview(reviewID uint, status, note string) (*models.Review, error)
+	VoteReview(reviewID, userID uint, helpful bool) (*models.Review, error)
+	RemoveVote(reviewID, userID uint) (*models.Review, error)
+	// RepairRatings сверяет сводки рейтинга товаров с отзывами.
+	RepairRatings() error
+	ReplyToReview(reviewID, authorID uint, body string) (*models.ReviewReply, error)
+	DeleteReply(reviewID, replyID uint) error
+	ReportReview(reviewID, userID uint, reason, comment string) error
+	GetReviewReports(reviewID uint) ([]models.ReviewReport, error)
 }
 
 type reviewService struct {
-	reviewRepo  repositories.ReviewRepository
-	productRepo repositories.ProductRepository
+	reviewRepo       repositories.ReviewRepository
+	productRepo      repositories.ProductRepository
+	orderRepo        repositories.OrderRepository
+	notificationRepo repositories.NotificationRepository
+	requirePurchase  bool
+	reportThreshold  int
 }
 
-func NewReviewService(reviewRepo repositories.ReviewRepository, productRepo repositories.ProductRepository) ReviewService {
+func NewReviewService(
+	reviewRepo repositories.ReviewRepository,
+	productRepo repositories.ProductRepository,
+	orderRepo repositories.OrderRepository,
+	notificationRepo repositories.NotificationRepository,
+	requirePurchase bool,
+	reportThreshold int,
+) ReviewService {
 	return &reviewService{
-		reviewRepo:  reviewRepo,
-		productRepo: productRepo,
+		reviewRepo:       reviewRepo,
+		productRepo:      productRepo,
+		orderRepo:        orderRepo,
+		notificationRepo: notificationRepo,
+		requirePurchase:  requirePurchase,
+		reportThreshold:  reportThreshold,
 	}
 }
 
-func (s *reviewService) CreateReview(userID uint, productID uint, rating int, comment string) (*models.Review, error) {
+func (s *reviewService) CreateReview(userID uint, productID uint, rating int, comment string, photos []string) (*models.Review, error) {
 
 	_, err := s.productRepo.FindByID(productID)
 	if err != nil {
@@ -40,11 +89,28 @@ func (s *reviewService) CreateReview(userID uint, productID uint, rating int, co
 		return nil, fmt.Errorf("you have already reviewed this product")
 	}
 
+	if err := validateReviewPhotos(photos); err != nil {
+		return nil, err
+	}
+
+	verified, err := s.orderRepo.HasCompletedPurchase(userID, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check purchase: %w", err)
+	}
+	if s.requirePurchase && !verified {
+		return nil, fmt.Errorf("only customers who bought this product can review it")
+	}
+
 	review := &models.Review{
-		UserID:    userID,
-		ProductID: productID,
-		Rating:    rating,
-		Comment:   comment,
+		UserID:           userID,
+		ProductID:        productID,
+		Rating:           rating,
+		Comment:          comment,
+		VerifiedPurchase: verified,
+		Status:           models.ReviewStatusPending,
+	}
+	for _, url := range photos {
+		review.Photos = append(review.Photos, models.ReviewPhoto{URL: url})
 	}
 
 	if err := s.reviewRepo.Create(review); err != nil {
@@ -55,7 +121,7 @@ func (s *reviewService) CreateReview(userID uint, productID uint, rating int, co
 	return s.reviewRepo.FindByID(review.ID)
 }
 
-func (s *reviewService) UpdateReview(reviewID uint, userID uint, rating int, comment string) (*models.Review, error) {
+func (s *reviewService) UpdateReview(reviewID uint, userID uint, rating int, comment string, photos []string) (*models.Review, error) {
 	review, err := s.reviewRepo.FindByID(reviewID)
 	if err != nil {
 		return nil, fmt.Errorf("review not found")
@@ -65,13 +131,24 @@ func (s *reviewService) UpdateReview(reviewID uint, userID uint, rating int, com
 	if review.UserID != userID {
 		return nil, fmt.Errorf("access denied")
 	}
+	if err := validateReviewPhotos(photos); err != nil {
+		return nil, err
+	}
 
 	review.Rating = rating
 	review.Comment = comment
+	review.Status = models.ReviewStatusPending
+	review.ModerationNote = ""
+	review.ModeratedAt = nil
 
 	if err := s.reviewRepo.Update(review); err != nil {
 		return nil, fmt.Errorf("failed to update review: %w", err)
 	}
+	if photos != nil {
+		if err := s.reviewRepo.ReplacePhotos(review.ID, photos); err != nil {
+			return nil, fmt.Errorf("failed to update review photos: %w", err)
+		}
+	}
 
 	return s.reviewRepo.FindByID(review.ID)
 }
@@ -90,18 +167,39 @@ func (s *reviewService) DeleteReview(reviewID uint, userID uint) error {
 	return s.reviewRepo.Delete(reviewID)
 }
 
-func (s *reviewService) GetProductReviews(productID uint) ([]models.Review, float64, int, error) {
-	reviews, err := s.reviewRepo.FindByProductID(productID)
+func (s *reviewService) GetProductReviews(productID uint, query repositories.ReviewQuery) (*ProductReviews, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 || query.Limit > 100 {
+		query.Limit = 20
+	}
+
+	reviews, total, err := s.reviewRepo.FindByProductID(productID, query)
 	if err != nil {
-		return nil, 0, 0, fmt.Errorf("failed to get reviews: %w", err)
+		return nil, fmt.Errorf("failed to get reviews: %w", err)
 	}
 
 	avgRating, totalCount, err := s.reviewRepo.GetProductStats(productID)
 	if err != nil {
-		return nil, 0, 0, fmt.Errorf("failed to get review stats: %w", err)
+		return nil, fmt.Errorf("failed to get review stats: %w", err)
+	}
+
+	histogram, err := s.reviewRepo.GetRatingHistogram(productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating histogram: %w", err)
 	}
 
-	return reviews, avgRating, totalCount, nil
+	return &ProductReviews{
+		Reviews:    reviews,
+		Total:      total,
+		Page:       query.Page,
+		Limit:      query.Limit,
+		Pages:      (total + int64(query.Limit) - 1) / int64(query.Limit),
+		AvgRating:  avgRating,
+		TotalCount: totalCount,
+		Histogram:  histogram,
+	}, nil
 }
 
 func (s *reviewService) GetUserReviews(userID uint) ([]models.Review, error) {
@@ -110,4 +208,199 @@ func (s *reviewService) GetUserReviews(userID uint) ([]models.Review, error) {
 
 func (s *reviewService) GetReviewByID(reviewID uint) (*models.Review, error) {
 	return s.reviewRepo.FindByID(reviewID)
-}
\ No newline at end of file
+}
+
+func (s *reviewService) GetModerationQueue(status string, page, limit int) ([]models.Review, int64, error) {
+	if status == "" {
+		status = models.ReviewStatusPending
+	}
+	if !isReviewStatus(status) {
+		return nil, 0, fmt.Errorf("invalid review status")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.reviewRepo.FindByStatus(status, page, limit)
+}
+
+func (s *reviewService) ModerateReview(reviewID uint, status, note string) (*models.Review, error) {
+	if status != models.ReviewStatusApproved && status != models.ReviewStatusRejected {
+		return nil, fmt.Errorf("status must be approved or rejected")
+	}
+	review, err := s.reviewRepo.FindByID(reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("review not found")
+	}
+
+	now := time.Now()
+	review.Status = status
+	review.ModerationNote = strings.TrimSpace(note)
+	review.ModeratedAt = &now
+	if err := s.reviewRepo.Update(review); err != nil {
+		return nil, fmt.Errorf("failed to moderate review: %w", err)
+	}
+	// Одобренный модератором отзыв снова скрывается только по новым жалобам.
+	if status == models.ReviewStatusApproved {
+		if err := s.reviewRepo.ResetReports(review.ID); err != nil {
+			return nil, fmt.Errorf("failed to reset review reports: %w", err)
+		}
+		review.ReportCount = 0
+	}
+
+	title, message, kind := "Отзыв опубликован", fmt.Sprintf("Ваш отзыв о товаре «%s» опубликован.", review.Product.Name), "success"
+	if status == models.ReviewStatusRejected {
+		title, message, kind = "Отзыв отклонён", fmt.Sprintf("Ваш отзыв о товаре «%s» не прошёл модерацию.", review.Product.Name), "warning"
+		if review.ModerationNote != "" {
+			message += " Причина: " + review.ModerationNote
+		}
+	}
+	_ = s.notificationRepo.Create(&models.Notification{
+		UserID:  review.UserID,
+		Title:   title,
+		Message: message,
+		Type:    kind,
+	})
+
+	return review, nil
+}
+
+func isReviewStatus(status string) bool {
+	switch status {
+	case models.ReviewStatusPending, models.ReviewStatusApproved, models.ReviewStatusRejected:
+		return true
+	}
+	return false
+}
+
+func validateReviewPhotos(photos []string) error {
+	if len(photos) > maxReviewPhotos {
+		return fmt.Errorf("a review can have at most %d photos", maxReviewPhotos)
+	}
+	for _, url := range photos {
+		name := strings.TrimPrefix(url, reviewPhotoURLPrefix)
+		if name == url || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("photos must be uploaded via /api/reviews/photos")
+		}
+	}
+	return nil
+}
+
+// Голосовать можно только за опубликованные чужие отзывы; повторный голос
+// заменяет прежний.
+func (s *reviewService) VoteReview(reviewID, userID uint, helpful bool) (*models.Review, error) {
+	review, err := s.reviewRepo.FindByID(reviewID)
+	if err != nil || review.Status != models.ReviewStatusApproved {
+		return nil, fmt.Errorf("review not found")
+	}
+	if review.UserID == userID {
+		return nil, fmt.Errorf("you cannot vote for your own review")
+	}
+
+	if err := s.reviewRepo.SaveVote(&models.ReviewVote{ReviewID: reviewID, UserID: userID, Helpful: helpful}); err != nil {
+		return nil, fmt.Errorf("failed to save vote: %w", err)
+	}
+	return s.reviewRepo.FindByID(reviewID)
+}
+
+func (s *reviewService) RemoveVote(reviewID, userID uint) (*models.Review, error) {
+	deleted, err := s.reviewRepo.DeleteVote(reviewID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove vote: %w", err)
+	}
+	if !deleted {
+		return nil, fmt.Errorf("vote not found")
+	}
+	return s.reviewRepo.FindByID(reviewID)
+}
+
+func (s *reviewService) RepairRatings() error {
+	fixed, err := s.reviewRepo.RepairProductRatings()
+	if err != nil {
+		return fmt.Errorf("failed to repair product ratings: %w", err)
+	}
+	if fixed > 0 {
+		log.Printf("⭐ Product rating aggregates repaired: %d", fixed)
+	}
+	return nil
+}
+
+func (s *reviewService) ReplyToReview(reviewID, authorID uint, body string) (*models.ReviewReply, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, fmt.Errorf("reply text is required")
+	}
+	review, err := s.reviewRepo.FindByID(reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("review not found")
+	}
+
+	reply := &models.ReviewReply{ReviewID: reviewID, AuthorID: authorID, Body: body}
+	if err := s.reviewRepo.CreateReply(reply); err != nil {
+		return nil, fmt.Errorf("failed to save reply: %w", err)
+	}
+
+	_ = s.notificationRepo.Create(&models.Notification{
+		UserID:  review.UserID,
+		Title:   "Магазин ответил на ваш отзыв",
+		Message: fmt.Sprintf("На ваш отзыв о товаре «%s» пришёл ответ: %s", review.Product.Name, body),
+		Type:    "info",
+	})
+	return reply, nil
+}
+
+func (s *reviewService) DeleteReply(reviewID, replyID uint) error {
+	deleted, err := s.reviewRepo.DeleteReply(reviewID, replyID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("reply not found")
+	}
+	return nil
+}
+
+func (s *reviewService) ReportReview(reviewID, userID uint, reason, comment string) error {
+	if !isReportReason(reason) {
+		return fmt.Errorf("reason must be one of: %s", strings.Join(models.ReviewReportReasons, ", "))
+	}
+	review, err := s.reviewRepo.FindByID(reviewID)
+	if err != nil || review.Status != models.ReviewStatusApproved {
+		return fmt.Errorf("review not found")
+	}
+	if review.UserID == userID {
+		return fmt.Errorf("you cannot report your own review")
+	}
+
+	hidden, err := s.reviewRepo.AddReport(&models.ReviewReport{
+		ReviewID: reviewID,
+		UserID:   userID,
+		Reason:   reason,
+		Comment:  strings.TrimSpace(comment),
+	}, s.reportThreshold)
+	if errors.Is(err, repositories.ErrAlreadyReported) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to report review: %w", err)
+	}
+	if hidden {
+		log.Printf("🚩 Review %d hidden for moderation after %d reports", reviewID, s.reportThreshold)
+	}
+	return nil
+}
+
+func (s *reviewService) GetReviewReports(reviewID uint) ([]models.ReviewReport, error) {
+	return s.reviewRepo.FindReports(reviewID)
+}
+
+func isReportReason(reason string) bool {
+	for _, r := range models.ReviewReportReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
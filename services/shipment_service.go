@@ -0,0 +1,248 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"1kosmetika-marketplace-backend/carriers"
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/repositories"
+	"1kosmetika-marketplace-backend/utils"
+)
+
+const (
+	// Отправление опрашивается у перевозчика не чаще этого интервала.
+	shipmentPollInterval = 30 * time.Minute
+	shipmentPollBatch    = 100
+)
+
+var shipmentStatuses = map[string]bool{
+	models.ShipmentStatusCreated:        true,
+	models.ShipmentStatusInTransit:      true,
+	models.ShipmentStatusOutForDelivery: true,
+	models.ShipmentStatusDelivered:      true,
+	models.ShipmentStatusFailed:         true,
+	models.ShipmentStatusReturned:       true,
+}
+
+// Тексты уведомлений для ключевых событий; о прочих покупателя не беспокоим.
+var shipmentNotices = map[string]struct{ title, message, kind string }{
+	models.ShipmentStatusInTransit:      {"Заказ в пути", "Ваш заказ #%d передан в службу доставки.", "info"},
+	models.ShipmentStatusOutForDelivery: {"Заказ у курьера", "Курьер уже везёт ваш заказ #%d.", "info"},
+	models.ShipmentStatusDelivered:      {"Заказ доставлен", "Ваш заказ #%d доставлен. Спасибо за покупку!", "success"},
+	models.ShipmentStatusFailed:         {"Не удалось доставить заказ", "Курьер не смог доставить заказ #%d. Мы свяжемся с вами.", "warning"},
+	models.ShipmentStatusReturned:       {"Заказ возвращён", "Заказ #%d возвращён отправителю.", "warning"},
+}
+
+type ShipmentService interface {
+	CreateShipment(orderID uint, carrierCode, trackingNumber string) (*models.Shipment, error)
+	AddEvent(shipmentID uint, event carriers.TrackingEvent) (*models.Shipment, error)
+	HandleWebhook(carrierCode, trackingNumber string, events []carriers.TrackingEvent) error
+	PollShipments(now time.Time) error
+	GetOrderTracking(orderID, userID uint, isAdmin bool) ([]models.Shipment, error)
+}
+
+type shipmentService struct {
+	shipmentRepo     repositories.ShipmentRepository
+	orderRepo        repositories.OrderRepository
+	notificationRepo repositories.NotificationRepository
+	carriers         *carriers.Registry
+}
+
+func NewShipmentService(
+	shipmentRepo repositories.ShipmentRepository,
+	orderRepo repositories.OrderRepository,
+	notificationRepo repositories.NotificationRepository,
+	registry *carriers.Registry,
+) ShipmentService {
+	return &shipmentService{
+		shipmentRepo:     shipmentRepo,
+		orderRepo:        orderRepo,
+		notificationRepo: notificationRepo,
+		carriers:         registry,
+	}
+}
+
+func (s *shipmentService) CreateShipment(orderID uint, carrierCode, trackingNumber string) (*models.Shipment, error) {
+	if _, err := s.carriers.Get(carrierCode); err != nil {
+		return nil, err
+	}
+	trackingNumber = strings.TrimSpace(trackingNumber)
+	if trackingNumber == "" {
+		return nil, fmt.Errorf("tracking number is required")
+	}
+
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("order not found")
+	}
+	if order.Status == models.OrderStatusCancelled || order.Status == models.OrderStatusRefunded {
+		return nil, fmt.Errorf("cannot ship order in status %q", order.Status)
+	}
+	if _, err := s.shipmentRepo.FindByTracking(carrierCode, trackingNumber); err == nil {
+		return nil, fmt.Errorf("shipment with this tracking number already exists")
+	}
+
+	shipment := &models.Shipment{
+		OrderID:        orderID,
+		Carrier:        carrierCode,
+		TrackingNumber: trackingNumber,
+		Status:         models.ShipmentStatusCreated,
+	}
+	if err := s.shipmentRepo.Create(shipment); err != nil {
+		return nil, fmt.Errorf("failed to create shipment: %w", err)
+	}
+	_, _ = s.shipmentRepo.AddEvent(&models.ShipmentEvent{
+		ShipmentID:  shipment.ID,
+		Status:      models.ShipmentStatusCreated,
+		Description: "Отправление создано",
+		OccurredAt:  shipment.CreatedAt,
+	})
+
+	if order.Status == models.OrderStatusPending || order.Status == models.OrderStatusProcessing {
+		if err := s.orderRepo.UpdateStatus(order.ID, models.OrderStatusShipped); err != nil {
+			return nil, fmt.Errorf("failed to update order status: %w", err)
+		}
+	}
+
+	return s.shipmentRepo.FindByID(shipment.ID)
+}
+
+func (s *shipmentService) AddEvent(shipmentID uint, event carriers.TrackingEvent) (*models.Shipment, error) {
+	shipment, err := s.shipmentRepo.FindByID(shipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("shipment not found")
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	if err := s.applyEvents(shipment, []carriers.TrackingEvent{event}); err != nil {
+		return nil, err
+	}
+	return s.shipmentRepo.FindByID(shipmentID)
+}
+
+func (s *shipmentService) HandleWebhook(carrierCode, trackingNumber string, events []carriers.TrackingEvent) error {
+	shipment, err := s.shipmentRepo.FindByTracking(carrierCode, trackingNumber)
+	if err != nil {
+		return fmt.Errorf("shipment not found")
+	}
+	return s.applyEvents(shipment, events)
+}
+
+func (s *shipmentService) PollShipments(now time.Time) error {
+	shipments, err := s.shipmentRepo.FindToPoll(carriers.ManualCode, now.Add(-shipmentPollInterval), shipmentPollBatch)
+	if err != nil {
+		return fmt.Errorf("failed to find shipments to poll: %w", err)
+	}
+
+	for i := range shipments {
+		shipment := &shipments[i]
+		carrier, err := s.carriers.Get(shipment.Carrier)
+		if err != nil {
+			continue
+		}
+
+		events, err := carrier.Track(shipment.TrackingNumber)
+		if err != nil {
+			log.Printf("❌ Tracking %s/%s failed: %v", shipment.Carrier, shipment.TrackingNumber, err)
+		} else if err := s.applyEvents(shipment, events); err != nil {
+			log.Printf("❌ Failed to apply tracking events for shipment %d: %v", shipment.ID, err)
+		}
+
+		checkedAt := now
+		shipment.LastCheckedAt = &checkedAt
+		_ = s.shipmentRepo.Update(shipment)
+	}
+	return nil
+}
+
+func (s *shipmentService) GetOrderTracking(orderID, userID uint, isAdmin bool) ([]models.Shipment, error) {
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("order not found")
+	}
+	if !isAdmin && (order.UserID == nil || *order.UserID != userID) {
+		return nil, fmt.Errorf("order not found")
+	}
+	return s.shipmentRepo.FindByOrderID(orderID)
+}
+
+// applyEvents записывает новые события, переводит отправление в статус самого
+// позднего из них и реагирует на смену статуса.
+func (s *shipmentService) applyEvents(shipment *models.Shipment, events []carriers.TrackingEvent) error {
+	var latestAt time.Time
+	if n := len(shipment.Events); n > 0 {
+		latestAt = shipment.Events[n-1].OccurredAt
+	}
+	status := shipment.Status
+
+	for _, e := range events {
+		if !shipmentStatuses[e.Status] {
+			return fmt.Errorf("unknown shipment status %q", e.Status)
+		}
+		created, err := s.shipmentRepo.AddEvent(&models.ShipmentEvent{
+			ShipmentID:  shipment.ID,
+			Status:      e.Status,
+			Description: e.Description,
+			Location:    e.Location,
+			OccurredAt:  e.OccurredAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save shipment event: %w", err)
+		}
+		if created && !e.OccurredAt.Before(latestAt) {
+			latestAt = e.OccurredAt
+			status = e.Status
+		}
+	}
+
+	if status == shipment.Status {
+		return nil
+	}
+
+	shipment.Status = status
+	if status == models.ShipmentStatusDelivered {
+		deliveredAt := latestAt
+		shipment.DeliveredAt = &deliveredAt
+	}
+	if err := s.shipmentRepo.Update(shipment); err != nil {
+		return fmt.Errorf("failed to update shipment: %w", err)
+	}
+
+	order, err := s.orderRepo.FindByID(shipment.OrderID)
+	if err != nil {
+		return nil
+	}
+	if status == models.ShipmentStatusDelivered && order.Status == models.OrderStatusShipped {
+		_ = s.orderRepo.UpdateStatus(order.ID, models.OrderStatusCompleted)
+	}
+	s.notify(order, status)
+	return nil
+}
+
+func (s *shipmentService) notify(order *models.Order, status string) {
+	notice, ok := shipmentNotices[status]
+	if !ok {
+		return
+	}
+	message := fmt.Sprintf(notice.message, order.ID)
+
+	email := order.GuestEmail
+	if order.UserID != nil {
+		_ = s.notificationRepo.Create(&models.Notification{
+			UserID:  *order.UserID,
+			Title:   notice.title,
+			Message: message,
+			Type:    notice.kind,
+		})
+		email = order.User.Email
+	}
+	if email != "" {
+		if err := utils.SendEmail(email, notice.title, "<p>"+message+"</p>"); err != nil {
+			log.Println("❌ Ошибка при отправке email:", err)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"1kosmetika-marketplace-backend/carriers"
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/repositories"
+)
+
+type fakeShipmentRepo struct {
+	repositories.ShipmentRepository
+	events  []models.ShipmentEvent
+	updated []models.Shipment
+}
+
+// AddEvent повторяет уникальность (shipment, status, occurred_at) из базы.
+func (r *fakeShipmentRepo) AddEvent(event *models.ShipmentEvent) (bool, error) {
+	for _, e := range r.events {
+		if e.ShipmentID == event.ShipmentID && e.Status == event.Status && e.OccurredAt.Equal(event.OccurredAt) {
+			return false, nil
+		}
+	}
+	r.events = append(r.events, *event)
+	return true, nil
+}
+
+func (r *fakeShipmentRepo) Update(shipment *models.Shipment) error {
+	r.updated = append(r.updated, *shipment)
+	return nil
+}
+
+type fakeOrderRepo struct {
+	repositories.OrderRepository
+	order    models.Order
+	statuses []string
+}
+
+func (r *fakeOrderRepo) FindByID(id uint) (*models.Order, error) {
+	order := r.order
+	return &order, nil
+}
+
+func (r *fakeOrderRepo) UpdateStatus(orderID uint, status string) error {
+	r.statuses = append(r.statuses, status)
+	r.order.Status = status
+	return nil
+}
+
+type fakeNotificationRepo struct {
+	repositories.NotificationRepository
+	created []models.Notification
+}
+
+func (r *fakeNotificationRepo) Create(notification *models.Notification) error {
+	r.created = append(r.created, *notification)
+	return nil
+}
+
+func TestShipmentApplyEvents(t *testing.T) {
+	base := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	userID := uint(7)
+
+	tests := []struct {
+		name        string
+		status      string
+		history     []models.ShipmentEvent // уже сохранённые события
+		guest       bool
+		events      []carriers.TrackingEvent
+		wantStatus  string
+		wantTitles  []string
+		wantOrder   []string
+		wantDeliver bool
+		wantErr     bool
+	}{
+		{
+			name:       "picked up",
+			status:     models.ShipmentStatusCreated,
+			events:     []carriers.TrackingEvent{{Status: models.ShipmentStatusInTransit, OccurredAt: base}},
+			wantStatus: models.ShipmentStatusInTransit,
+			wantTitles: []string{"Заказ в пути"},
+		},
+		{
+			name:   "full history at once notifies about the latest status only",
+			status: models.ShipmentStatusCreated,
+			events: []carriers.TrackingEvent{
+				{Status: models.ShipmentStatusInTransit, OccurredAt: base},
+				{Status: models.ShipmentStatusOutForDelivery, OccurredAt: base.Add(24 * time.Hour)},
+				{Status: models.ShipmentStatusDelivered, OccurredAt: base.Add(30 * time.Hour)},
+			},
+			wantStatus:  models.ShipmentStatusDelivered,
+			wantTitles:  []string{"Заказ доставлен"},
+			wantOrder:   []string{models.OrderStatusCompleted},
+			wantDeliver: true,
+		},
+		{
+			name:       "repeated event changes nothing",
+			status:     models.ShipmentStatusInTransit,
+			history:    []models.ShipmentEvent{{Status: models.ShipmentStatusInTransit, OccurredAt: base}},
+			events:     []carriers.TrackingEvent{{Status: models.ShipmentStatusInTransit, OccurredAt: base}},
+			wantStatus: models.ShipmentStatusInTransit,
+		},
+		{
+			name:       "late event does not roll status back",
+			status:     models.ShipmentStatusOutForDelivery,
+			history:    []models.ShipmentEvent{{Status: models.ShipmentStatusOutForDelivery, OccurredAt: base.Add(24 * time.Hour)}},
+			events:     []carriers.TrackingEvent{{Status: models.ShipmentStatusInTransit, OccurredAt: base}},
+			wantStatus: models.ShipmentStatusOutForDelivery,
+		},
+		{
+			name:       "failed delivery warns the customer",
+			status:     models.ShipmentStatusOutForDelivery,
+			events:     []carriers.TrackingEvent{{Status: models.ShipmentStatusFailed, OccurredAt: base}},
+			wantStatus: models.ShipmentStatusFailed,
+			wantTitles: []string{"Не удалось доставить заказ"},
+		},
+		{
+			name:       "guest order gets no in-app notification",
+			status:     models.ShipmentStatusCreated,
+			guest:      true,
+			events:     []carriers.TrackingEvent{{Status: models.ShipmentStatusInTransit, OccurredAt: base}},
+			wantStatus: models.ShipmentStatusInTransit,
+		},
+		{
+			name:       "unknown status is rejected",
+			status:     models.ShipmentStatusCreated,
+			events:     []carriers.TrackingEvent{{Status: "teleported", OccurredAt: base}},
+			wantStatus: models.ShipmentStatusCreated,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shipments := &fakeShipmentRepo{}
+			order := models.Order{ID: 42, Status: models.OrderStatusShipped}
+			if !tt.guest {
+				order.UserID = &userID
+			}
+			orders := &fakeOrderRepo{order: order}
+			notifications := &fakeNotificationRepo{}
+			svc := &shipmentService{
+				shipmentRepo:     shipments,
+				orderRepo:        orders,
+				notificationRepo: notifications,
+				carriers:         carriers.NewRegistry(carriers.NewManualCarrier()),
+			}
+
+			shipment := &models.Shipment{ID: 1, OrderID: order.ID, Status: tt.status}
+			for _, e := range tt.history {
+				e.ShipmentID = shipment.ID
+				shipment.Events = append(shipment.Events, e)
+				shipments.events = append(shipments.events, e)
+			}
+
+			err := svc.applyEvents(shipment, tt.events)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyEvents error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if shipment.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", shipment.Status, tt.wantStatus)
+			}
+			if tt.wantStatus == tt.status && len(shipments.updated) != 0 {
+				t.Errorf("shipment saved without a status change")
+			}
+			if (shipment.DeliveredAt != nil) != tt.wantDeliver {
+				t.Errorf("delivered_at = %v, want set: %v", shipment.DeliveredAt, tt.wantDeliver)
+			}
+
+			if len(orders.statuses) != len(tt.wantOrder) {
+				t.Fatalf("order status updates = %v, want %v", orders.statuses, tt.wantOrder)
+			}
+			for i := range tt.wantOrder {
+				if orders.statuses[i] != tt.wantOrder[i] {
+					t.Errorf("order status update %d = %q, want %q", i, orders.statuses[i], tt.wantOrder[i])
+				}
+			}
+
+			if len(notifications.created) != len(tt.wantTitles) {
+				t.Fatalf("notifications = %+v, want titles %v", notifications.created, tt.wantTitles)
+			}
+			for i, n := range notifications.created {
+				if n.Title != tt.wantTitles[i] || n.UserID != userID {
+					t.Errorf("notification %d = %+v, want %q for user %d", i, n, tt.wantTitles[i], userID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
+	"1kosmetika-marketplace-backend/repositories"
+)
+
+// ShippingQuote — стоимость доставки выбранным способом для конкретного адреса.
+type ShippingQuote struct {
+	MethodID      uint         `json:"method_id"`
+	Code          string       `json:"code"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	EstimatedDays string       `json:"estimated_days"`
+	Price         money.Amount `json:"price"`
+	FreeShipping  bool         `json:"free_shipping"`
+}
+
+type ShippingService interface {
+	GetMethods(activeOnly bool) ([]models.ShippingMethod, error)
+	GetMethod(methodID uint) (*models.ShippingMethod, error)
+	CreateMethod(method *models.ShippingMethod) error
+	UpdateMethod(methodID uint, input *models.ShippingMethod) (*models.ShippingMethod, error)
+	DeleteMethod(methodID uint) error
+
+	AddRate(methodID uint, rate *models.ShippingRate) error
+	UpdateRate(methodID, rateID uint, input *models.ShippingRate) (*models.ShippingRate, error)
+	DeleteRate(methodID, rateID uint) error
+
+	Quote(methodID uint, region, city string, subtotal money.Amount) (*ShippingQuote, error)
+	QuoteAll(region, city string, subtotal money.Amount) ([]ShippingQuote, error)
+}
+
+type shippingService struct {
+	shippingRepo repositories.ShippingRepository
+}
+
+func NewShippingService(shippingRepo repositories.ShippingRepository) ShippingService {
+	return &shippingService{shippingRepo: shippingRepo}
+}
+
+func (s *shippingService) GetMethods(activeOnly bool) ([]models.ShippingMethod, error) {
+	return s.shippingRepo.FindMethods(activeOnly)
+}
+
+func (s *shippingService) GetMethod(methodID uint) (*models.ShippingMethod, error) {
+	return s.shippingRepo.FindMethodByID(methodID)
+}
+
+func (s *shippingService) CreateMethod(method *models.ShippingMethod) error {
+	if method.FreeShippingThreshold < 0 {
+		return fmt.Errorf("free shipping threshold cannot be negative")
+	}
+	method.ID = 0
+	method.Code = strings.ToLower(strings.TrimSpace(method.Code))
+	return s.shippingRepo.CreateMethod(method)
+}
+
+func (s *shippingService) UpdateMethod(methodID uint, input *models.ShippingMethod) (*models.ShippingMethod, error) {
+	method, err := s.shippingRepo.FindMethodByID(methodID)
+	if err != nil {
+		return nil, fmt.Errorf("shipping method not found")
+	}
+	if input.FreeShippingThreshold < 0 {
+		return nil, fmt.Errorf("free shipping threshold cannot be negative")
+	}
+
+	method.Code = strings.ToLower(strings.TrimSpace(input.Code))
+	method.Name = input.Name
+	method.Description = input.Description
+	method.FreeShippingThreshold = input.FreeShippingThreshold
+	method.EstimatedDays = input.EstimatedDays
+	method.IsActive = input.IsActive
+
+	if err := s.shippingRepo.UpdateMethod(method); err != nil {
+		return nil, err
+	}
+	return method, nil
+}
+
+func (s *shippingService) DeleteMethod(methodID uint) error {
+	if _, err := s.shippingRepo.FindMethodByID(methodID); err != nil {
+		return fmt.Errorf("shipping method not found")
+	}
+	return s.shippingRepo.DeleteMethod(methodID)
+}
+
+func (s *shippingService) AddRate(methodID uint, rate *models.ShippingRate) error {
+	if _, err := s.shippingRepo.FindMethodByID(methodID); err != nil {
+		return fmt.Errorf("shipping method not found")
+	}
+	if err := normalizeRate(rate); err != nil {
+		return err
+	}
+	rate.ID = 0
+	rate.ShippingMethodID = methodID
+	return s.shippingRepo.CreateRate(rate)
+}
+
+func (s *shippingService) UpdateRate(methodID, rateID uint, input *models.ShippingRate) (*models.ShippingRate, error) {
+	rate, err := s.shippingRepo.FindRateByID(rateID)
+	if err != nil || rate.ShippingMethodID != methodID {
+		return nil, fmt.Errorf("shipping rate not found")
+	}
+	if err := normalizeRate(input); err != nil {
+		return nil, err
+	}
+
+	rate.Region = input.Region
+	rate.City = input.City
+	rate.Price = input.Price
+
+	if err := s.shippingRepo.UpdateRate(rate); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (s *shippingService) DeleteRate(methodID, rateID uint) error {
+	rate, err := s.shippingRepo.FindRateByID(rateID)
+	if err != nil || rate.ShippingMethodID != methodID {
+		return fmt.Errorf("shipping rate not found")
+	}
+	return s.shippingRepo.DeleteRate(rateID)
+}
+
+func (s *shippingService) Quote(methodID uint, region, city string, subtotal money.Amount) (*ShippingQuote, error) {
+	method, err := s.shippingRepo.FindMethodByID(methodID)
+	if err != nil || !method.IsActive {
+		return nil, fmt.Errorf("shipping method not available")
+	}
+	quote, ok := quoteMethod(method, region, city, subtotal)
+	if !ok {
+		return nil, fmt.Errorf("shipping method %q does not deliver to this address", method.Name)
+	}
+	return quote, nil
+}
+
+// QuoteAll возвращает только способы, которые доставляют по указанному адресу.
+func (s *shippingService) QuoteAll(region, city string, subtotal money.Amount) ([]ShippingQuote, error) {
+	methods, err := s.shippingRepo.FindMethods(true)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := make([]ShippingQuote, 0, len(methods))
+	for i := range methods {
+		if quote, ok := quoteMethod(&methods[i], region, city, subtotal); ok {
+			quotes = append(quotes, *quote)
+		}
+	}
+	return quotes, nil
+}
+
+// Тариф выбирается от частного к общему: город, затем велаят, затем вся страна.
+func quoteMethod(method *models.ShippingMethod, region, city string, subtotal money.Amount) (*ShippingQuote, bool) {
+	region = strings.ToLower(strings.TrimSpace(region))
+	city = strings.TrimSpace(city)
+
+	var cityRate, regionRate, countryRate *models.ShippingRate
+	for i := range method.Rates {
+		rate := &method.Rates[i]
+		switch {
+		case rate.Region == "":
+			countryRate = rate
+		case rate.Region != region:
+		case rate.City == "":
+			regionRate = rate
+		case strings.EqualFold(rate.City, city):
+			cityRate = rate
+		}
+	}
+
+	rate := cityRate
+	if rate == nil {
+		rate = regionRate
+	}
+	if rate == nil {
+		rate = countryRate
+	}
+	if rate == nil {
+		return nil, false
+	}
+
+	quote := &ShippingQuote{
+		MethodID:      method.ID,
+		Code:          method.Code,
+		Name:          method.Name,
+		Description:   method.Description,
+		EstimatedDays: method.EstimatedDays,
+		Price:         rate.Price,
+	}
+	if method.FreeShippingThreshold > 0 && subtotal >= method.FreeShippingThreshold {
+		quote.Price = 0
+		quote.FreeShipping = true
+	}
+	return quote, true
+}
+
+func normalizeRate(rate *models.ShippingRate) error {
+	if rate.Price < 0 {
+		return fmt.Errorf("shipping price cannot be negative")
+	}
+	rate.City = strings.TrimSpace(rate.City)
+	if strings.TrimSpace(rate.Region) == "" {
+		if rate.City != "" {
+			return fmt.Errorf("region is required when city is set")
+		}
+		rate.Region = ""
+		return nil
+	}
+	region, err := normalizeRegion(rate.Region)
+	if err != nil {
+		return err
+	}
+	rate.Region = region
+	return nil
+}
@@ -18,8 +18,8 @@ func (s *StatsService) GetMonthlyStats() ([]repositories.MonthlyStats, error) {
 	return s.repo.GetMonthlyStats()
 }
 
-func (s *StatsService) GetPopularProducts(limit int) ([]repositories.ProductStats, error) {
-	return s.repo.GetPopularProducts(limit)
+func (s *StatsService) GetPopularProducts(limit int, sortBy string) ([]repositories.ProductStats, error) {
+	return s.repo.GetPopularProducts(limit, sortBy)
 }
 
 func (s *StatsService) GetSalesByCategory() ([]repositories.CategoryStats, error) {
@@ -70,3 +70,7 @@ func (s *StatsService) GetRefundStats() (repositories.RefundStats, error) {
 func (s *StatsService) GetProfitStats() (repositories.ProfitStats, error) {
 	return s.repo.GetProfitStats()
 }
+
+func (s *StatsService) GetAbandonedCartStats(thresholdHours int) (repositories.AbandonedCartStats, error) {
+	return s.repo.GetAbandonedCartStats(thresholdHours)
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
+	"1kosmetika-marketplace-backend/repositories"
+	"1kosmetika-marketplace-backend/utils"
+)
+
+// Столько подписок обрабатывается за один запуск планировщика: популярное
+// поступление рассылается частями, а не в запросе, изменившем остаток.
+const stockAlertBatchSize = 200
+
+type StockAlertService interface {
+	Subscribe(productID uint, userID *uint, email string) error
+	Unsubscribe(productID, userID uint) error
+	// ProcessPending отправляет очередную партию уведомлений о поступлении.
+	ProcessPending(now time.Time) error
+}
+
+type stockAlertService struct {
+	subscriptionRepo repositories.StockSubscriptionRepository
+	productRepo      repositories.ProductRepository
+	notificationRepo repositories.NotificationRepository
+}
+
+func NewStockAlertService(
+	subscriptionRepo repositories.StockSubscriptionRepository,
+	productRepo repositories.ProductRepository,
+	notificationRepo repositories.NotificationRepository,
+) StockAlertService {
+	return &stockAlertService{
+		subscriptionRepo: subscriptionRepo,
+		productRepo:      productRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *stockAlertService) Subscribe(productID uint, userID *uint, email string) error {
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil || product.Status != models.ProductStatusActive {
+		return fmt.Errorf("product not found")
+	}
+	if product.Stock > 0 {
+		return fmt.Errorf("product is in stock")
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if _, err := mail.ParseAddress(email); err != nil {
+		return fmt.Errorf("valid email is required")
+	}
+
+	return s.subscriptionRepo.Upsert(&models.StockSubscription{
+		ProductID: productID,
+		UserID:    userID,
+		Email:     email,
+	})
+}
+
+func (s *stockAlertService) Unsubscribe(productID, userID uint) error {
+	deleted, err := s.subscriptionRepo.DeleteByUser(productID, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("subscription not found")
+	}
+	return nil
+}
+
+func (s *stockAlertService) ProcessPending(now time.Time) error {
+	subs, err := s.subscriptionRepo.FindPending(stockAlertBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to load stock subscriptions: %w", err)
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(subs))
+	for _, sub := range subs {
+		ids = append(ids, sub.ProductID)
+	}
+	products, err := s.productRepo.FindByIDs(ids)
+	if err != nil {
+		return fmt.Errorf("failed to load products: %w", err)
+	}
+	byID := make(map[uint]*models.Product, len(products))
+	for i := range products {
+		byID[products[i].ID] = &products[i]
+	}
+
+	sent := 0
+	for _, sub := range subs {
+		product, ok := byID[sub.ProductID]
+		if !ok {
+			continue
+		}
+
+		// Отмечаем до отправки: при сбое письмо потеряется, но не уйдёт дважды.
+		if err := s.subscriptionRepo.MarkNotified(sub.ID, now); err != nil {
+			log.Printf("❌ Failed to mark stock subscription %d: %v", sub.ID, err)
+			continue
+		}
+
+		message := fmt.Sprintf("«%s» снова в наличии по цене %s.", product.Name, money.Format(product.Price))
+		if sub.UserID != nil {
+			_ = s.notificationRepo.Create(&models.Notification{
+				UserID:  *sub.UserID,
+				Title:   "Товар снова в наличии",
+				Message: message,
+				Type:    "success",
+			})
+		}
+
+		body := fmt.Sprintf(`
+	<h2>Товар снова в наличии</h2>
+	<p>%s</p>
+	<p>Успейте оформить заказ, пока он не закончился.</p>
+`, html.EscapeString(message))
+		if err := utils.SendEmail(sub.Email, "Товар снова в наличии", body); err != nil {
+			log.Printf("❌ Failed to send back-in-stock email for subscription %d: %v", sub.ID, err)
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		log.Printf("📦 Back-in-stock notifications sent: %d", sent)
+	}
+	return nil
+}
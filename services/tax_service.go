@@ -0,0 +1,155 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/money"
+	"1kosmetika-marketplace-backend/repositories"
+)
+
+type TaxService interface {
+	GetTaxRates() ([]models.TaxRate, error)
+	CreateTaxRate(rate *models.TaxRate) error
+	UpdateTaxRate(id uint, input *models.TaxRate) (*models.TaxRate, error)
+	DeleteTaxRate(id uint) error
+	// ApplyTaxes заполняет налог в строках заказа и итог налога в заказе.
+	ApplyTaxes(order *models.Order, items []models.OrderProduct, products []models.Product) error
+}
+
+type taxService struct {
+	taxRepo          repositories.TaxRepository
+	productRepo      repositories.ProductRepository
+	pricesIncludeTax bool
+}
+
+func NewTaxService(taxRepo repositories.TaxRepository, productRepo repositories.ProductRepository, pricesIncludeTax bool) TaxService {
+	return &taxService{
+		taxRepo:          taxRepo,
+		productRepo:      productRepo,
+		pricesIncludeTax: pricesIncludeTax,
+	}
+}
+
+func (s *taxService) GetTaxRates() ([]models.TaxRate, error) {
+	return s.taxRepo.FindAll()
+}
+
+func (s *taxService) CreateTaxRate(rate *models.TaxRate) error {
+	if err := s.validate(rate); err != nil {
+		return err
+	}
+	rate.ID = 0
+	return s.taxRepo.Create(rate)
+}
+
+func (s *taxService) UpdateTaxRate(id uint, input *models.TaxRate) (*models.TaxRate, error) {
+	rate, err := s.taxRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("tax rate not found")
+	}
+	if err := s.validate(input); err != nil {
+		return nil, err
+	}
+
+	rate.Name = input.Name
+	rate.Rate = input.Rate
+	rate.ProductID = input.ProductID
+	rate.Category = input.Category
+	rate.IsActive = input.IsActive
+
+	if err := s.taxRepo.Update(rate); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (s *taxService) DeleteTaxRate(id uint) error {
+	if _, err := s.taxRepo.FindByID(id); err != nil {
+		return fmt.Errorf("tax rate not found")
+	}
+	return s.taxRepo.Delete(id)
+}
+
+func (s *taxService) validate(rate *models.TaxRate) error {
+	if rate.Rate < 0 || rate.Rate > 100 {
+		return fmt.Errorf("tax rate must be between 0 and 100")
+	}
+	rate.Category = strings.TrimSpace(rate.Category)
+	if rate.ProductID != nil && rate.Category != "" {
+		return fmt.Errorf("tax rate applies either to a product or to a category")
+	}
+	if rate.ProductID != nil {
+		if _, err := s.productRepo.FindByID(*rate.ProductID); err != nil {
+			return fmt.Errorf("product not found")
+		}
+	}
+	return nil
+}
+
+// Налог не начисляется на доставку: ставки задаются только для товаров.
+func (s *taxService) ApplyTaxes(order *models.Order, items []models.OrderProduct, products []models.Product) error {
+	rates, err := s.taxRepo.FindActive()
+	if err != nil {
+		return fmt.Errorf("failed to get tax rates: %w", err)
+	}
+
+	categories := make(map[uint]string, len(products))
+	for _, p := range products {
+		categories[p.ID] = p.Category
+	}
+
+	order.PricesIncludeTax = s.pricesIncludeTax
+	order.TaxTotal = 0
+	for i := range items {
+		item := &items[i]
+		rate := resolveTaxRate(rates, item.ProductID, categories[item.ProductID])
+		item.TaxRate = rate
+		gross := item.Price.Mul(item.Quantity)
+		if s.pricesIncludeTax {
+			item.TaxAmount = gross.MulRate(rate / (100 + rate))
+			item.NetAmount = gross - item.TaxAmount
+		} else {
+			item.TaxAmount = gross.MulRate(rate / 100)
+			item.NetAmount = gross
+		}
+		order.TaxTotal += item.TaxAmount
+	}
+	return nil
+}
+
+func resolveTaxRate(rates []models.TaxRate, productID uint, category string) float64 {
+	var byCategory, byDefault *models.TaxRate
+	for i := range rates {
+		r := &rates[i]
+		switch {
+		case r.ProductID != nil:
+			if *r.ProductID == productID {
+				return r.Rate
+			}
+		case r.Category != "":
+			if strings.EqualFold(r.Category, category) {
+				byCategory = r
+			}
+		default:
+			byDefault = r
+		}
+	}
+	if byCategory != nil {
+		return byCategory.Rate
+	}
+	if byDefault != nil {
+		return byDefault.Rate
+	}
+	return 0
+}
+
+// orderTotal — итог к оплате с учётом режима цен.
+func orderTotal(order *models.Order) money.Amount {
+	total := order.Subtotal + order.ShippingCost
+	if !order.PricesIncludeTax {
+		total += order.TaxTotal
+	}
+	return total
+}
@@ -14,6 +14,7 @@ type UserService interface {
 	UpdateRole(userID uint, role string) error
 	GetAllUsers() ([]models.User, error)
 	DeleteUser(userID uint) error
+	UpdatePreferences(userID uint, cartReminders bool) (*models.User, error)
 }
 
 type userService struct {
@@ -75,4 +76,16 @@ func (s *userService) GetAllUsers() ([]models.User, error) {
 
 func (s *userService) DeleteUser(userID uint) error {
 	return s.userRepo.Delete(userID)
-}
\ No newline at end of file
+}
+
+func (s *userService) UpdatePreferences(userID uint, cartReminders bool) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	user.CartRemindersOptOut = !cartReminders
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update preferences: %w", err)
+	}
+	return user, nil
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"1kosmetika-marketplace-backend/models"
+	"1kosmetika-marketplace-backend/repositories"
+
+	"github.com/google/uuid"
+)
+
+// SharedWishlist — публичное представление списка по ссылке: без владельца
+// и настроек уведомлений, только активные товары.
+type SharedWishlist struct {
+	Name      string           `json:"name"`
+	Products  []models.Product `json:"products"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+type WishlistService interface {
+	GetWishlists(userID uint) ([]models.Wishlist, error)
+	GetWishlist(userID, id uint) (*models.Wishlist, error)
+	CreateWishlist(userID uint, name string) (*models.Wishlist, error)
+	RenameWishlist(userID, id uint, name string) (*models.Wishlist, error)
+	DeleteWishlist(userID, id uint) error
+	AddItem(userID, id, productID uint) error
+	RemoveItem(userID, id, productID uint) error
+	Share(userID, id uint) (*models.Wishlist, error)
+	Unshare(userID, id uint) error
+	GetShared(token string) (*SharedWishlist, error)
+}
+
+type wishlistService struct {
+	wishlistRepo repositories.WishlistRepository
+	favoriteRepo repositories.FavoriteRepository
+	productRepo  repositories.ProductRepository
+}
+
+func NewWishlistService(
+	wishlistRepo repositories.WishlistRepository,
+	favoriteRepo repositories.FavoriteRepository,
+	productRepo repositories.ProductRepository,
+) WishlistService {
+	return &wishlistService{
+		wishlistRepo: wishlistRepo,
+		favoriteRepo: favoriteRepo,
+		productRepo:  productRepo,
+	}
+}
+
+// Список по умолчанию создаётся при первом обращении, поэтому он есть всегда.
+func (s *wishlistService) GetWishlists(userID uint) ([]models.Wishlist, error) {
+	if _, err := s.wishlistRepo.FindOrCreateDefault(userID); err != nil {
+		return nil, err
+	}
+	return s.wishlistRepo.FindByUserID(userID)
+}
+
+func (s *wishlistService) GetWishlist(userID, id uint) (*models.Wishlist, error) {
+	return s.findOwned(userID, id)
+}
+
+func (s *wishlistService) CreateWishlist(userID uint, name string) (*models.Wishlist, error) {
+	name, err := normalizeWishlistName(name)
+	if err != nil {
+		return nil, err
+	}
+	wishlist := &models.Wishlist{UserID: userID, Name: name}
+	if err := s.wishlistRepo.Create(wishlist); err != nil {
+		return nil, err
+	}
+	return wishlist, nil
+}
+
+func (s *wishlistService) RenameWishlist(userID, id uint, name string) (*models.Wishlist, error) {
+	name, err := normalizeWishlistName(name)
+	if err != nil {
+		return nil, err
+	}
+	wishlist, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	wishlist.Name = name
+	if err := s.wishlistRepo.Update(wishlist); err != nil {
+		return nil, err
+	}
+	return wishlist, nil
+}
+
+func (s *wishlistService) DeleteWishlist(userID, id uint) error {
+	wishlist, err := s.findOwned(userID, id)
+	if err != nil {
+		return err
+	}
+	if wishlist.IsDefault {
+		return fmt.Errorf("default wishlist cannot be deleted")
+	}
+	return s.wishlistRepo.Delete(id)
+}
+
+func (s *wishlistService) AddItem(userID, id, productID uint) error {
+	if _, err := s.findOwned(userID, id); err != nil {
+		return err
+	}
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil || product.Status != models.ProductStatusActive {
+		return fmt.Errorf("product not found")
+	}
+
+	exists, err := s.favoriteRepo.ExistsInWishlist(id, productID)
+	if err != nil {
+		return fmt.Errorf("failed to check wishlist")
+	}
+	if exists {
+		return fmt.Errorf("product already in wishlist")
+	}
+
+	return s.favoriteRepo.Create(&models.Favorite{
+		UserID:     userID,
+		WishlistID: &id,
+		ProductID:  productID,
+	})
+}
+
+func (s *wishlistService) RemoveItem(userID, id, productID uint) error {
+	if _, err := s.findOwned(userID, id); err != nil {
+		return err
+	}
+	deleted, err := s.favoriteRepo.DeleteFromWishlist(id, productID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("product not in wishlist")
+	}
+	return nil
+}
+
+// Share выдаёт ссылку только для чтения; повторный вызов возвращает ту же ссылку.
+func (s *wishlistService) Share(userID, id uint) (*models.Wishlist, error) {
+	wishlist, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if wishlist.ShareToken != nil {
+		return wishlist, nil
+	}
+	token := strings.ReplaceAll(uuid.NewString(), "-", "")
+	wishlist.ShareToken = &token
+	if err := s.wishlistRepo.Update(wishlist); err != nil {
+		return nil, err
+	}
+	return wishlist, nil
+}
+
+func (s *wishlistService) Unshare(userID, id uint) error {
+	wishlist, err := s.findOwned(userID, id)
+	if err != nil {
+		return err
+	}
+	wishlist.ShareToken = nil
+	return s.wishlistRepo.Update(wishlist)
+}
+
+func (s *wishlistService) GetShared(token string) (*SharedWishlist, error) {
+	if token == "" {
+		return nil, fmt.Errorf("wishlist not found")
+	}
+	wishlist, err := s.wishlistRepo.FindByShareToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("wishlist not found")
+	}
+
+	shared := &SharedWishlist{
+		Name:      wishlist.Name,
+		Products:  make([]models.Product, 0, len(wishlist.Items)),
+		UpdatedAt: wishlist.UpdatedAt,
+	}
+	for _, item := range wishlist.Items {
+		if item.Product.ID != 0 && item.Product.Status == models.ProductStatusActive {
+			shared.Products = append(shared.Products, item.Product)
+		}
+	}
+	return shared, nil
+}
+
+func (s *wishlistService) findOwned(userID, id uint) (*models.Wishlist, error) {
+	wishlist, err := s.wishlistRepo.FindByID(id)
+	if err != nil || wishlist.UserID != userID {
+		return nil, fmt.Errorf("wishlist not found")
+	}
+	return wishlist, nil
+}
+
+func normalizeWishlistName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("wishlist name is required")
+	}
+	if len([]rune(name)) > 100 {
+		return "", fmt.Errorf("wishlist name is too long")
+	}
+	return name, nil
+}
@@ -1,12 +1,29 @@
 package utils
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"mime"
+	"mime/multipart"
 	"net/smtp"
+	"net/textproto"
 	"os"
 )
 
+type EmailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
 func SendEmail(to string, subject string, body string) error {
+	return SendEmailWithAttachments(to, subject, body, nil)
+}
+
+// SendEmailWithAttachments отправляет HTML-письмо; вложения кодируются в base64
+// внутри multipart/mixed.
+func SendEmailWithAttachments(to string, subject string, body string, attachments []EmailAttachment) error {
 	from := os.Getenv("FROM_EMAIL")
 	user := os.Getenv("SMTP_USER")
 	password := os.Getenv("SMTP_PASS")
@@ -19,13 +36,66 @@ func SendEmail(to string, subject string, body string) error {
 
 	addr := fmt.Sprintf("%s:%s", host, port)
 
-	msg := []byte("To: " + to + "\r\n" +
+	var msg bytes.Buffer
+	msg.WriteString("To: " + to + "\r\n" +
 		"From: " + from + "\r\n" +
-		"Subject: " + subject + "\r\n" +
-		"MIME-Version: 1.0\r\n" +
-		"Content-Type: text/html; charset=UTF-8\r\n" +
-		"\r\n" + body)
+		"Subject: " + mime.QEncoding.Encode("UTF-8", subject) + "\r\n" +
+		"MIME-Version: 1.0\r\n")
+
+	if len(attachments) == 0 {
+		msg.WriteString("Content-Type: text/html; charset=UTF-8\r\n\r\n" + body)
+	} else if err := writeMultipart(&msg, body, attachments); err != nil {
+		return err
+	}
 
 	auth := smtp.PlainAuth("", user, password, host)
-	return smtp.SendMail(addr, auth, from, []string{to}, msg)
+	return smtp.SendMail(addr, auth, from, []string{to}, msg.Bytes())
+}
+
+func writeMultipart(msg *bytes.Buffer, body string, attachments []EmailAttachment) error {
+	var parts bytes.Buffer
+	w := multipart.NewWriter(&parts)
+
+	htmlPart, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"text/html; charset=UTF-8"},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := htmlPart.Write([]byte(body)); err != nil {
+		return err
+	}
+
+	for _, a := range attachments {
+		part, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := part.Write(base64Lines(a.Data)); err != nil {
+			return err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	msg.WriteString("Content-Type: multipart/mixed; boundary=" + w.Boundary() + "\r\n\r\n")
+	msg.Write(parts.Bytes())
+	return nil
+}
+
+// По RFC 2045 строки base64 не длиннее 76 символов.
+func base64Lines(data []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var out bytes.Buffer
+	for len(encoded) > 76 {
+		out.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	out.WriteString(encoded + "\r\n")
+	return out.Bytes()
 }
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	GuestCookieName  = "guest_cart"
+	GuestTokenHeader = "X-Guest-Token"
+)
+
+func signGuestID(id string) string {
+	mac := hmac.New(sha256.New, jwtSecretBytes())
+	mac.Write([]byte(id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateGuestToken возвращает идентификатор гостя и подписанный токен для cookie.
+func GenerateGuestToken() (string, string) {
+	id := uuid.NewString()
+	return id, id + "." + signGuestID(id)
+}
+
+// ParseGuestToken проверяет подпись и возвращает идентификатор гостя.
+func ParseGuestToken(signed string) (string, error) {
+	id, sig, ok := strings.Cut(signed, ".")
+	if !ok || id == "" {
+		return "", fmt.Errorf("invalid guest token")
+	}
+	if !hmac.Equal([]byte(sig), []byte(signGuestID(id))) {
+		return "", fmt.Errorf("invalid guest token signature")
+	}
+	return id, nil
+}
+
+// GuestTokenFromRequest ищет подписанный токен гостя в cookie или заголовке X-Guest-Token.
+func GuestTokenFromRequest(c *gin.Context) (string, bool) {
+	signed, err := c.Cookie(GuestCookieName)
+	if err != nil || signed == "" {
+		signed = c.GetHeader(GuestTokenHeader)
+	}
+	if signed == "" {
+		return "", false
+	}
+	id, err := ParseGuestToken(signed)
+	if err != nil {
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+const maxSlugLength = 120
+
+// Таблица транслитерации: русская кириллица, туркменская кириллица
+// и туркменская латиница со специальными буквами.
+var translitMap = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sch", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+
+	'ә': "a", 'ө': "o", 'ү': "u", 'җ': "j", 'ң': "n",
+
+	'ä': "a", 'ç': "ch", 'ň': "n", 'ö': "o", 'ş': "sh", 'ü': "u", 'ý': "y", 'ž': "zh",
+}
+
+// Transliterate переводит кириллицу и туркменские буквы в латиницу,
+// сохраняя регистр первой буквы и все остальные символы.
+func Transliterate(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		lower := unicode.ToLower(r)
+		repl, ok := translitMap[lower]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+		if lower != r && repl != "" {
+			repl = strings.ToUpper(repl[:1]) + repl[1:]
+		}
+		b.WriteString(repl)
+	}
+	return b.String()
+}
+
+// Slugify строит URL-slug: транслитерация, нижний регистр,
+// всё кроме латиницы и цифр заменяется одиночным дефисом.
+func Slugify(s string) string {
+	s = strings.ToLower(Transliterate(s))
+
+	var b strings.Builder
+	dash := false
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.Trim(b.String(), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}